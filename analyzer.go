@@ -2,85 +2,125 @@ package main
 
 import (
 	"bufio"
-	"regexp"
 	"strings"
 	"time"
 )
 
 // AnalyzeLogs analyzes pod logs and extracts errors, warnings, and info
-func AnalyzeLogs(logs string) LogAnalysis {
-	analysis := LogAnalysis{
-		Errors:   make([]string, 0),
-		Warnings: make([]string, 0),
-		Info:     make([]string, 0),
-		RawLogs:  logs, // EN ÖNEMLİSİ: Raw log'ları sakla!
+// using the given rules, or the built-in rules when nil
+func AnalyzeLogs(logs string, rules *RuleSet) LogAnalysis {
+	analysis := newLogAnalysis(rules)
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	for scanner.Scan() {
+		analysis.classify(scanner.Text(), time.Time{})
 	}
+	analysis.finish()
+	return analysis
+}
 
-	scanner := bufio.NewScanner(strings.NewReader(logs))
+// newLogAnalysis starts an analysis whose lines are then classified one by
+// one
+func newLogAnalysis(rules *RuleSet) LogAnalysis {
+	if rules == nil {
+		rules = defaultRuleSet
+	}
 
-	// Enhanced regex patterns for better detection
-	errorPatterns := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)\b(error|err|exception|fatal|panic|crash|failed|failure)\b`),
-		regexp.MustCompile(`(?i)\b(stack\s+trace|stacktrace)\b`),
-		regexp.MustCompile(`(?i)\b(connection\s+(refused|failed|timeout))\b`),
-		regexp.MustCompile(`(?i)\b(out\s+of\s+memory|oom)\b`),
-		regexp.MustCompile(`(?i)\b(permission\s+denied|access\s+denied)\b`),
+	return LogAnalysis{
+		Errors:       make([]string, 0),
+		Warnings:     make([]string, 0),
+		Info:         make([]string, 0),
+		RuleHits:     make(map[string]int),
+		FormatCounts: make(map[string]int),
+		rules:        rules,
 	}
+}
 
-	warningPatterns := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)\b(warn|warning|deprecated|timeout|retry|retrying)\b`),
-		regexp.MustCompile(`(?i)\b(slow\s+query|performance)\b`),
-		regexp.MustCompile(`(?i)\b(connection\s+lost|reconnecting)\b`),
+// finish closes the last stack trace once every line was classified
+func (a *LogAnalysis) finish() {
+	if a.traceOpen {
+		a.Traces[len(a.Traces)-1].close()
 	}
 
-	infoPatterns := []*regexp.Regexp{
-		regexp.MustCompile(`(?i)\b(info|starting|started|listening|ready|success|successful|completed)\b`),
-		regexp.MustCompile(`(?i)\b(connected|initialized|loaded)\b`),
+	// Analiz zamanını ekle
+	a.AnalyzedAt = time.Now()
+}
+
+// AddLine analyzes a streamed line, logged at the given time, and updates
+// the counters
+func (a *LogAnalysis) AddLine(line string, at time.Time) {
+	a.classify(line, at)
+	a.AnalyzedAt = time.Now()
+}
+
+// classify parses a single line and counts it under its category. The
+// level of structured lines decides the category; rules only classify
+// unstructured lines, or lines whose level is unknown. Stack trace frames
+// are attached to the trace's header line and not counted on their own.
+// at is when the API says the line was logged, zero when unknown; it is
+// used when the line carries no timestamp of its own.
+func (a *LogAnalysis) classify(line string, at time.Time) {
+	a.TotalLines++
+
+	entry := ParseLogLine(line)
+	entry.Line = a.TotalLines
+	if entry.Time.IsZero() {
+		entry.Time = at
 	}
+	if at.After(a.LastLogged) {
+		a.LastLogged = at
+	}
+	if a.FormatCounts == nil {
+		a.FormatCounts = make(map[string]int)
+	}
+	a.FormatCounts[entry.Format]++
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		analysis.TotalLines++
+	if a.continueTrace(&entry) {
+		a.Entries = append(a.Entries, entry)
+		return
+	}
 
-		isError := false
-		isWarning := false
+	rules := a.rules
+	if rules == nil {
+		rules = defaultRuleSet
+	}
 
-		// Check for errors
-		for _, pattern := range errorPatterns {
-			if pattern.MatchString(line) {
-				analysis.ErrorCount++
-				analysis.Errors = append(analysis.Errors, line)
-				isError = true
-				break
-			}
+	category := levelCategory(entry.Level)
+	var rule *Rule
+	if category == "" {
+		rule = rules.Match(line)
+		if rule != nil {
+			category = rule.Category
 		}
-
-		// Check for warnings (only if not already an error)
-		if !isError {
-			for _, pattern := range warningPatterns {
-				if pattern.MatchString(line) {
-					analysis.WarningCount++
-					analysis.Warnings = append(analysis.Warnings, line)
-					isWarning = true
-					break
-				}
-			}
+	} else if match := rules.Match(entry.Message); match != nil && match.Category == category {
+		// Still credit the rule so its hint is shown
+		rule = match
+	}
+	if category == "" {
+		if _, body := splitTag(line); isTraceStart(body) {
+			category = CategoryError
 		}
+	}
+	entry.Category = category
+	a.Entries = append(a.Entries, entry)
+	a.noteTraceHeader(entry)
 
-		// Check for info (only if not already an error or warning)
-		if !isError && !isWarning {
-			for _, pattern := range infoPatterns {
-				if pattern.MatchString(line) {
-					analysis.InfoCount++
-					analysis.Info = append(analysis.Info, line)
-					break
-				}
-			}
+	if rule != nil {
+		if a.RuleHits == nil {
+			a.RuleHits = make(map[string]int)
 		}
+		a.RuleHits[rule.Name]++
 	}
 
-	// Analiz zamanını ekle
-	analysis.AnalyzedAt = time.Now()
-
-	return analysis
+	switch category {
+	case CategoryError:
+		a.ErrorCount++
+		a.Errors = append(a.Errors, line)
+		a.addSignature(entry)
+	case CategoryWarning:
+		a.WarningCount++
+		a.Warnings = append(a.Warnings, line)
+	case CategoryInfo:
+		a.InfoCount++
+		a.Info = append(a.Info, line)
+	}
 }
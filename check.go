@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Exit codes of the check subcommand. Threshold violations are bit flags so
+// a pipeline can tell which categories failed from the exit code alone.
+const (
+	ExitOK                 = 0
+	ExitRuntimeError       = 1
+	ExitUsageError         = 2
+	ExitErrorsExceeded     = 4
+	ExitWarningsExceeded   = 8
+	ExitRestartsExceeded   = 16
+	ExitNotReadyExceeded   = 32
+	ExitUnreadableExceeded = 64
+)
+
+// Check rule identifiers used in reports
+const (
+	RuleMaxErrors     = "max-errors"
+	RuleMaxWarnings   = "max-warnings"
+	RuleMaxRestarts   = "max-restarts"
+	RuleMaxNotReady   = "max-not-ready"
+	RuleMaxUnreadable = "max-unreadable"
+)
+
+// CheckThresholds holds the limits of a check run; negative values disable
+// the corresponding rule
+type CheckThresholds struct {
+	MaxErrors   int // Per pod
+	MaxWarnings int // Per pod
+	MaxRestarts int // Per pod
+	MaxNotReady int // Across the namespace
+	// Pods whose logs cannot be read, and so whose errors and warnings are
+	// not checked, across the namespace
+	MaxUnreadable int
+}
+
+// Violation is a threshold exceeded by a pod
+type Violation struct {
+	Pod     string
+	Rule    string
+	Actual  int
+	Limit   int
+	Message string
+	Details []string // Sample log lines backing the violation
+}
+
+// CheckResult is the outcome of evaluating thresholds against a namespace
+type CheckResult struct {
+	Namespace  string
+	Pods       []string
+	Violations []Violation
+}
+
+// ExitCode returns the combined exit code of the violated rules
+func (r CheckResult) ExitCode() int {
+	code := ExitOK
+	for _, v := range r.Violations {
+		switch v.Rule {
+		case RuleMaxErrors:
+			code |= ExitErrorsExceeded
+		case RuleMaxWarnings:
+			code |= ExitWarningsExceeded
+		case RuleMaxRestarts:
+			code |= ExitRestartsExceeded
+		case RuleMaxNotReady:
+			code |= ExitNotReadyExceeded
+		case RuleMaxUnreadable:
+			code |= ExitUnreadableExceeded
+		}
+	}
+	return code
+}
+
+// EvaluateChecks applies the thresholds to the analyzed pods of a namespace
+func EvaluateChecks(namespace string, results []podAnalysis, thresholds CheckThresholds) CheckResult {
+	result := CheckResult{Namespace: namespace}
+
+	var notReady []string
+	var unreadable []podAnalysis
+	for _, r := range results {
+		result.Pods = append(result.Pods, r.pod.Name)
+		if r.err != nil {
+			unreadable = append(unreadable, r)
+		}
+
+		if thresholds.MaxErrors >= 0 && r.analysis.ErrorCount > thresholds.MaxErrors {
+			result.Violations = append(result.Violations, Violation{
+				Pod: r.pod.Name, Rule: RuleMaxErrors, Actual: r.analysis.ErrorCount, Limit: thresholds.MaxErrors,
+				Message: fmt.Sprintf("%d error lines (limit %d)", r.analysis.ErrorCount, thresholds.MaxErrors),
+				Details: sampleLines(r.analysis.Errors),
+			})
+		}
+		if thresholds.MaxWarnings >= 0 && r.analysis.WarningCount > thresholds.MaxWarnings {
+			result.Violations = append(result.Violations, Violation{
+				Pod: r.pod.Name, Rule: RuleMaxWarnings, Actual: r.analysis.WarningCount, Limit: thresholds.MaxWarnings,
+				Message: fmt.Sprintf("%d warning lines (limit %d)", r.analysis.WarningCount, thresholds.MaxWarnings),
+				Details: sampleLines(r.analysis.Warnings),
+			})
+		}
+		restarts, _ := strconv.Atoi(r.pod.Restarts)
+		if thresholds.MaxRestarts >= 0 && restarts > thresholds.MaxRestarts {
+			result.Violations = append(result.Violations, Violation{
+				Pod: r.pod.Name, Rule: RuleMaxRestarts, Actual: restarts, Limit: thresholds.MaxRestarts,
+				Message: fmt.Sprintf("%d restarts (limit %d)", restarts, thresholds.MaxRestarts),
+			})
+		}
+		// Completed pods are never ready and are not a problem
+		if r.pod.Ready != "True" && r.pod.Status != "Succeeded" && r.pod.Status != "Completed" {
+			notReady = append(notReady, r.pod.Name)
+		}
+	}
+
+	if thresholds.MaxNotReady >= 0 && len(notReady) > thresholds.MaxNotReady {
+		for _, pod := range notReady {
+			result.Violations = append(result.Violations, Violation{
+				Pod: pod, Rule: RuleMaxNotReady, Actual: len(notReady), Limit: thresholds.MaxNotReady,
+				Message: fmt.Sprintf("pod is not ready; %d pods not ready in namespace (limit %d)", len(notReady), thresholds.MaxNotReady),
+			})
+		}
+	}
+	if thresholds.MaxUnreadable >= 0 && len(unreadable) > thresholds.MaxUnreadable {
+		for _, r := range unreadable {
+			result.Violations = append(result.Violations, Violation{
+				Pod: r.pod.Name, Rule: RuleMaxUnreadable, Actual: len(unreadable), Limit: thresholds.MaxUnreadable,
+				Message: fmt.Sprintf("logs could not be read: %v; %d pods unreadable in namespace (limit %d)", r.err, len(unreadable), thresholds.MaxUnreadable),
+			})
+		}
+	}
+
+	return result
+}
+
+// sampleLines returns the most frequent lines formatted with their counts
+func sampleLines(lines []string) []string {
+	var samples []string
+	for _, line := range topLines(lines, 5) {
+		samples = append(samples, fmt.Sprintf("%dx %s", line.Count, line.Line))
+	}
+	return samples
+}
+
+// runCheck implements the `check` subcommand and returns the exit code
+func runCheck(args []string) int {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	namespace := fs.String("namespace", "default", "Namespace to check")
+	fs.StringVar(namespace, "n", "default", "Namespace to check (shorthand)")
+	selector := fs.String("selector", "", "Label selector to filter pods, e.g. app=api")
+	fs.StringVar(selector, "l", "", "Label selector (shorthand)")
+	fieldSelector := fs.String("field-selector", "", "Field selector to filter pods, e.g. status.phase!=Running")
+	since := fs.Duration("since", 10*time.Minute, "Log duration to analyze")
+	fs.DurationVar(since, "s", 10*time.Minute, "Log duration (shorthand)")
+	var thresholds CheckThresholds
+	fs.IntVar(&thresholds.MaxErrors, "max-errors", -1, "Maximum error lines per pod (-1 disables)")
+	fs.IntVar(&thresholds.MaxWarnings, "max-warnings", -1, "Maximum warning lines per pod (-1 disables)")
+	fs.IntVar(&thresholds.MaxRestarts, "max-restarts", -1, "Maximum container restarts per pod (-1 disables)")
+	fs.IntVar(&thresholds.MaxNotReady, "max-not-ready", -1, "Maximum number of not ready pods (-1 disables)")
+	fs.IntVar(&thresholds.MaxUnreadable, "max-unreadable", 0, "Maximum number of pods whose logs cannot be read (-1 disables)")
+	junit := fs.String("junit", "", "Write a JUnit XML report to this file")
+	sarif := fs.String("sarif", "", "Write a SARIF report to this file")
+	rulesPath := fs.String("rules", "", "Rules file (default: ~/.config/k8s-pod-log-analyzer/rules.yaml)")
+	var kubeOptions KubeOptions
+	fs.StringVar(&kubeOptions.Context, "context", "", "Kubeconfig context (default: current context)")
+	fs.StringVar(&kubeOptions.Kubeconfig, "kubeconfig", "", "Kubeconfig file (default: KUBECONFIG or ~/.kube/config)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsageError
+	}
+
+	podOptions := PodListOptions{LabelSelector: *selector, FieldSelector: *fieldSelector}
+	if err := podOptions.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid selector: %v\n", err)
+		return ExitUsageError
+	}
+
+	rules, err := LoadRuleConfig(*rulesPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid rules:\n%v\n", err)
+		return ExitUsageError
+	}
+
+	client, err := NewKubeClient(kubeOptions)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitRuntimeError
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	results, err := analyzeNamespace(ctx, client, *namespace, podOptions, timeRange{since: *since}, rules.ForNamespace(*namespace))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitRuntimeError
+	}
+	if thresholds.MaxUnreadable < 0 {
+		for _, r := range results {
+			if r.err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: logs of %s could not be read: %v\n", r.pod.Name, r.err)
+			}
+		}
+	}
+
+	result := EvaluateChecks(*namespace, results, thresholds)
+
+	if *junit != "" {
+		if err := writeOutput(*junit, func(w io.Writer) error { return WriteJUnit(w, result) }); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return ExitRuntimeError
+		}
+	}
+	if *sarif != "" {
+		if err := writeOutput(*sarif, func(w io.Writer) error { return WriteSARIF(w, result) }); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return ExitRuntimeError
+		}
+	}
+
+	for _, v := range result.Violations {
+		fmt.Printf("FAIL %s [%s]: %s\n", v.Pod, v.Rule, v.Message)
+	}
+	fmt.Printf("%d pods checked in %s, %d violations\n", len(result.Pods), *namespace, len(result.Violations))
+
+	return result.ExitCode()
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the check result as JUnit XML with one test case per
+// pod and one failure per violated rule
+func WriteJUnit(w io.Writer, result CheckResult) error {
+	byPod := make(map[string][]Violation)
+	for _, v := range result.Violations {
+		byPod[v.Pod] = append(byPod[v.Pod], v)
+	}
+
+	suite := junitTestSuite{Name: result.Namespace, Tests: len(result.Pods)}
+	for _, pod := range result.Pods {
+		testCase := junitTestCase{Name: pod, ClassName: "k8s-pod-log-analyzer." + result.Namespace}
+		for _, v := range byPod[pod] {
+			testCase.Failures = append(testCase.Failures, junitFailure{
+				Message: v.Message,
+				Type:    v.Rule,
+				Text:    strings.Join(v.Details, "\n"),
+			})
+		}
+		if len(testCase.Failures) > 0 {
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	suites := junitTestSuites{
+		Name:     "k8s-pod-log-analyzer",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// checkRuleDescriptions documents the rules in SARIF output
+var checkRuleDescriptions = []sarifRule{
+	{ID: RuleMaxErrors, ShortDescription: sarifMessage{Text: "Pod logged more error lines than allowed"}},
+	{ID: RuleMaxWarnings, ShortDescription: sarifMessage{Text: "Pod logged more warning lines than allowed"}},
+	{ID: RuleMaxRestarts, ShortDescription: sarifMessage{Text: "Pod containers restarted more often than allowed"}},
+	{ID: RuleMaxNotReady, ShortDescription: sarifMessage{Text: "More pods are not ready than allowed"}},
+	{ID: RuleMaxUnreadable, ShortDescription: sarifMessage{Text: "The logs of more pods could not be read than allowed"}},
+}
+
+// WriteSARIF writes the check result as a SARIF 2.1.0 log with one result
+// per violation, located at the offending pod. Code scanning tools only
+// accept results with a file location, so pods get a synthetic
+// k8s/<namespace>/<pod> path.
+func WriteSARIF(w io.Writer, result CheckResult) error {
+	results := make([]sarifResult, 0, len(result.Violations))
+	for _, v := range result.Violations {
+		message := v.Pod + ": " + v.Message
+		if len(v.Details) > 0 {
+			message += "\n" + strings.Join(v.Details, "\n")
+		}
+		results = append(results, sarifResult{
+			RuleID:  v.Rule,
+			Level:   "error",
+			Message: sarifMessage{Text: message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{
+					URI: "k8s/" + result.Namespace + "/" + v.Pod,
+				}},
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               v.Pod,
+					FullyQualifiedName: result.Namespace + "/" + v.Pod,
+					Kind:               "resource",
+				}},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "k8s-pod-log-analyzer",
+				InformationURI: "https://github.com/dgnydn/k8s-pod-log-analyzer",
+				Rules:          checkRuleDescriptions,
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
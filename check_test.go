@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestEvaluateChecks(t *testing.T) {
+	ready := func(name string) PodInfo {
+		return PodInfo{Name: name, Ready: "True", Status: "Running", Restarts: "0"}
+	}
+	healthy := podAnalysis{pod: ready("api")}
+	noisy := podAnalysis{pod: ready("worker"), analysis: LogAnalysis{ErrorCount: 3, WarningCount: 1}}
+	restarted := podAnalysis{pod: PodInfo{Name: "db", Ready: "True", Restarts: "4"}}
+	notReady := podAnalysis{pod: PodInfo{Name: "cache", Ready: "False", Status: "CrashLoopBackOff", Restarts: "0"}}
+	completed := podAnalysis{pod: PodInfo{Name: "migrate", Ready: "False", Status: "Completed", Restarts: "0"}}
+	unreadable := podAnalysis{pod: ready("proxy"), err: errors.New("forbidden")}
+	none := CheckThresholds{MaxErrors: -1, MaxWarnings: -1, MaxRestarts: -1, MaxNotReady: -1, MaxUnreadable: -1}
+
+	tests := []struct {
+		name       string
+		results    []podAnalysis
+		thresholds func(th *CheckThresholds)
+		want       []string // Pod and rule of every violation
+		wantCode   int
+	}{
+		{"healthy", []podAnalysis{healthy, completed}, func(th *CheckThresholds) {
+			*th = CheckThresholds{}
+		}, nil, ExitOK},
+		{"errors and warnings", []podAnalysis{healthy, noisy}, func(th *CheckThresholds) {
+			th.MaxErrors, th.MaxWarnings = 2, 0
+		}, []string{"worker/" + RuleMaxErrors, "worker/" + RuleMaxWarnings}, ExitErrorsExceeded | ExitWarningsExceeded},
+		{"at the limit", []podAnalysis{noisy}, func(th *CheckThresholds) {
+			th.MaxErrors = 3
+		}, nil, ExitOK},
+		{"restarts", []podAnalysis{restarted}, func(th *CheckThresholds) {
+			th.MaxRestarts = 3
+		}, []string{"db/" + RuleMaxRestarts}, ExitRestartsExceeded},
+		{"not ready", []podAnalysis{notReady, completed}, func(th *CheckThresholds) {
+			th.MaxNotReady = 0
+		}, []string{"cache/" + RuleMaxNotReady}, ExitNotReadyExceeded},
+		{"unreadable logs", []podAnalysis{healthy, unreadable}, func(th *CheckThresholds) {
+			th.MaxUnreadable = 0
+		}, []string{"proxy/" + RuleMaxUnreadable}, ExitUnreadableExceeded},
+		{"unreadable logs allowed", []podAnalysis{unreadable}, func(th *CheckThresholds) {
+			th.MaxUnreadable = 1
+		}, nil, ExitOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thresholds := none
+			tt.thresholds(&thresholds)
+			result := EvaluateChecks("ns", tt.results, thresholds)
+
+			var got []string
+			for _, v := range result.Violations {
+				got = append(got, v.Pod+"/"+v.Rule)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("violations %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("violation %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+			if code := result.ExitCode(); code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	result := CheckResult{
+		Namespace: "payments",
+		Pods:      []string{"api"},
+		Violations: []Violation{{
+			Pod: "api", Rule: RuleMaxErrors, Actual: 3, Limit: 0,
+			Message: "3 error lines (limit 0)", Details: []string{"3x ERROR boom"},
+		}},
+	}
+	var out bytes.Buffer
+	if err := WriteSARIF(&out, result); err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	results := log.Runs[0].Results
+	if len(results) != 1 {
+		t.Fatalf("%d results, want 1", len(results))
+	}
+	location := results[0].Locations[0]
+	if uri := location.PhysicalLocation.ArtifactLocation.URI; uri != "k8s/payments/api" {
+		t.Errorf("uri = %q, want k8s/payments/api", uri)
+	}
+	if name := location.LogicalLocations[0].FullyQualifiedName; name != "payments/api" {
+		t.Errorf("fully qualified name = %q, want payments/api", name)
+	}
+	if text := results[0].Message.Text; text != "api: 3 error lines (limit 0)\n3x ERROR boom" {
+		t.Errorf("message = %q", text)
+	}
+}
+
+func TestSubcommandHelp(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func([]string) int
+		args []string
+		want int
+	}{
+		{"check help", runCheck, []string{"-h"}, ExitOK},
+		{"check unknown flag", runCheck, []string{"--nope"}, ExitUsageError},
+		{"report help", runReport, []string{"-help"}, 0},
+		{"report unknown flag", runReport, []string{"--nope"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.run(tt.args); got != tt.want {
+				t.Errorf("exit code = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"io"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// requestTimeout bounds every non-streaming API call made by the commands
+const requestTimeout = 30 * time.Second
+
+// LogOptions controls which log stream is requested for a pod
+type LogOptions struct {
+	Container  string
+	Since      time.Duration
+	SinceTime  time.Time
+	Until      time.Time // Applied by fetchLogs to timestamped logs, the API has no end time
+	Previous   bool
+	Follow     bool
+	Timestamps bool
+}
+
+// PodListOptions narrows down the pods returned by ListPods
+type PodListOptions struct {
+	LabelSelector string
+	FieldSelector string // Such as status.phase!=Running
+}
+
+// ClusterClient is the set of Kubernetes operations the analyzer depends on.
+// The TUI only talks to the cluster through this interface so it can be
+// backed by client-go or by the in-memory FakeClient.
+type ClusterClient interface {
+	ListNamespaces(ctx context.Context) ([]string, error)
+	ListPods(ctx context.Context, namespace string, opts PodListOptions) ([]corev1.Pod, error)
+	StreamLogs(ctx context.Context, namespace, pod string, opts LogOptions) (io.ReadCloser, error)
+	ListEvents(ctx context.Context, namespace, object string) ([]corev1.Event, error)
+	ListReplicaSets(ctx context.Context, namespace string) ([]appsv1.ReplicaSet, error)
+}
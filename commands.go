@@ -1,93 +1,247 @@
 package main
 
 import (
-	"os/exec"
+	"context"
+	"fmt"
+	"io"
 	"strings"
+	"sync"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 // LoadNamespaces command to fetch Kubernetes namespaces
-func LoadNamespaces() tea.Cmd {
+func LoadNamespaces(client ClusterClient) tea.Cmd {
 	return func() tea.Msg {
-		cmd := exec.Command("kubectl", "get", "namespaces", "-o", "jsonpath={.items[*].metadata.name}")
-		output, err := cmd.Output()
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		namespaces, err := client.ListNamespaces(ctx)
 		if err != nil {
 			return LoadNamespacesMsg{err: err}
 		}
 
-		namespaceStr := strings.TrimSpace(string(output))
-		var namespaces []string
-		if namespaceStr != "" {
-			namespaces = strings.Fields(namespaceStr)
-		}
-
 		return LoadNamespacesMsg{namespaces: namespaces}
 	}
 }
 
-// LoadPods command to fetch pods in a namespace
-func LoadPods(namespace string) tea.Cmd {
+// LoadContexts command to read the contexts of the kubeconfig
+func LoadContexts(kubeconfig string) tea.Cmd {
+	return func() tea.Msg {
+		contexts, current, err := ListContexts(kubeconfig)
+		return LoadContextsMsg{contexts: contexts, current: current, err: err}
+	}
+}
+
+// SwitchContext command to connect to another kubeconfig context
+func SwitchContext(opts KubeOptions) tea.Cmd {
+	return func() tea.Msg {
+		client, err := NewKubeClient(opts)
+		if err != nil {
+			return SwitchContextMsg{err: err}
+		}
+		return SwitchContextMsg{client: client, context: client.Context()}
+	}
+}
+
+// LoadPods command to fetch the pods of a namespace matching opts
+func LoadPods(client ClusterClient, namespace string, opts PodListOptions) tea.Cmd {
 	return func() tea.Msg {
-		cmd := exec.Command("kubectl", "get", "pods", "-n", namespace, "-o", "custom-columns=NAME:.metadata.name,STATUS:.status.phase,READY:.status.conditions[?(@.type=='Ready')].status,RESTARTS:.status.containerStatuses[0].restartCount,AGE:.metadata.creationTimestamp", "--no-headers")
-		output, err := cmd.Output()
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		pods, err := listPodInfos(ctx, client, namespace, opts)
 		if err != nil {
 			return LoadPodsMsg{err: err}
 		}
 
-		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-		var pods []PodInfo
+		return LoadPodsMsg{pods: pods}
+	}
+}
+
+// ScanPods command to count the error lines in the logs of the given pods,
+// for the totals of the pod grid. Pods whose logs cannot be read are
+// recorded too, so they are not fetched again on every refresh.
+func ScanPods(client ClusterClient, namespace string, pods []PodInfo, window timeRange, rules *RuleSet) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), fanOutTimeout)
+		defer cancel()
+
+		scans := make(map[string]podScan, len(pods))
+		for _, result := range analyzePods(ctx, client, namespace, pods, window, rules) {
+			scan := podScan{errors: result.analysis.ErrorCount, restarts: result.pod.Restarts}
+			if result.err != nil {
+				scan.errors = -1
+			}
+			scans[podScanKey(result.pod.Context, namespace, result.pod.Name)] = scan
+		}
+		return ScanPodsMsg{window: window, scans: scans}
+	}
+}
+
+// listPodInfos lists the pods of a namespace as PodInfo
+func listPodInfos(ctx context.Context, client ClusterClient, namespace string, opts PodListOptions) ([]PodInfo, error) {
+	items, err := client.ListPods(ctx, namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := make([]PodInfo, 0, len(items))
+	ownedByReplicaSet := false
+	for _, item := range items {
+		pod := NewPodInfo(item)
+		ownedByReplicaSet = ownedByReplicaSet || strings.HasPrefix(pod.Workload, "ReplicaSet/")
+		pods = append(pods, pod)
+	}
+
+	// Replica sets are best effort: only the grouping of the pods needs them
+	if ownedByReplicaSet {
+		replicaSets, _ := client.ListReplicaSets(ctx, namespace)
+		resolveWorkloads(pods, items, replicaSets)
+	}
+	return pods, nil
+}
+
+// LoadLogs command to fetch and analyze pod logs along with the events of
+// the given objects. Containers that have restarted also get their previous
+// instance analyzed.
+func LoadLogs(client ClusterClient, namespace string, target logTarget, objects []string, window timeRange, rules *RuleSet) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
 
-		for _, line := range lines {
-			if line == "" {
-				continue
+		if target.workload != "" {
+			analysis, err := loadWorkloadLogs(ctx, client, namespace, target, window.logOptions(), rules)
+			if err != nil {
+				return LoadLogsMsg{target: target, err: err}
 			}
-			fields := strings.Fields(line)
-			if len(fields) >= 4 {
-				status := fields[1]
-				ready := "False"
-				if len(fields) > 2 && fields[2] != "<none>" {
-					ready = fields[2]
-				}
-				restarts := "0"
-				if len(fields) > 3 && fields[3] != "<none>" {
-					restarts = fields[3]
-				}
-				age := "Unknown"
-				if len(fields) > 4 {
-					age = CalculateAge(fields[4])
-				}
-
-				icon := GetStatusIcon(status, ready)
-
-				pods = append(pods, PodInfo{
-					Name:       fields[0],
-					Status:     status,
-					Ready:      ready,
-					Restarts:   restarts,
-					Age:        age,
-					StatusIcon: icon,
-				})
+			return LoadLogsMsg{target: target, analysis: analysis, events: loadEvents(ctx, client, namespace, objects)}
+		}
+
+		analysis, err := loadContainerLogs(ctx, client, namespace, target.pod, target.containers, window.logOptions(), rules)
+		if err != nil {
+			return LoadLogsMsg{target: target, err: err}
+		}
+		msg := LoadLogsMsg{target: target, analysis: analysis, events: loadEvents(ctx, client, namespace, objects)}
+
+		// The previous instance is best effort: the kubelet may have
+		// already garbage collected it. Its logs are fetched whole since the
+		// crash usually predates the --since window.
+		if len(target.restarted) > 0 {
+			previous, err := loadContainerLogs(ctx, client, namespace, target.pod, target.restarted, LogOptions{Previous: true}, rules)
+			if err == nil {
+				msg.previous = &previous
 			}
 		}
 
-		return LoadPodsMsg{pods: pods}
+		return msg
 	}
 }
 
-// LoadLogs command to fetch and analyze pod logs
-func LoadLogs(namespace, pod, since string) tea.Cmd {
+// RefreshLogs command to fetch the lines a target logged after the last
+// analyzed one and its current events, so the open analysis can be updated
+// incrementally. after is the API timestamp of the last analyzed line; when
+// zero, nothing was analyzed yet and the whole time range is fetched again.
+func RefreshLogs(client ClusterClient, namespace string, target logTarget, objects []string, window timeRange, after time.Time) tea.Cmd {
 	return func() tea.Msg {
-		cmd := exec.Command("kubectl", "logs", "-n", namespace, pod, "--since="+since)
-		output, err := cmd.Output()
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		opts := window.logOptions()
+		if !after.IsZero() {
+			// The API truncates sinceTime to whole seconds, linesAfter drops
+			// the lines that were already analyzed
+			opts = LogOptions{SinceTime: after}
+		}
+		opts.Timestamps = true
+
+		at := time.Now()
+		streams := target.streams()
+		var sources []taggedLogs
+		for _, stream := range streams {
+			opts.Container = stream.container
+			output, err := fetchLogs(ctx, client, namespace, stream.pod, opts)
+			if err != nil {
+				return RefreshLogsMsg{target: target, err: err}
+			}
+			sources = append(sources, taggedLogs{tag: stream.tag, logs: output})
+		}
+
+		return RefreshLogsMsg{
+			target: target,
+			lines:  linesAfter(sources, after, len(streams) > 1),
+			events: loadEvents(ctx, client, namespace, objects),
+			at:     at,
+		}
+	}
+}
+
+// loadContainerLogs fetches and analyzes the logs of one or more containers
+// of a pod. When several containers are given their lines are interleaved
+// by timestamp and tagged with the container name.
+func loadContainerLogs(ctx context.Context, client ClusterClient, namespace, pod string, containers []string, opts LogOptions, rules *RuleSet) (LogAnalysis, error) {
+	// Timestamps place the lines on the timeline of the pod's events
+	opts.Timestamps = true
+	if len(containers) == 1 {
+		opts.Container = containers[0]
+		output, err := fetchLogs(ctx, client, namespace, pod, opts)
 		if err != nil {
-			return LoadLogsMsg{pod: pod, err: err}
+			return LogAnalysis{}, err
 		}
+		return analyzeTimedLogs([]taggedLogs{{tag: containers[0], logs: output}}, false, rules), nil
+	}
+
+	outputs := make([]string, len(containers))
+	errs := make([]error, len(containers))
+	var wg sync.WaitGroup
+	for i, container := range containers {
+		containerOpts := opts
+		containerOpts.Container = container
+		wg.Add(1)
+		go func(i int, opts LogOptions) {
+			defer wg.Done()
+			outputs[i], errs[i] = fetchLogs(ctx, client, namespace, pod, opts)
+		}(i, containerOpts)
+	}
+	wg.Wait()
 
-		analysis := AnalyzeLogs(string(output))
-		return LoadLogsMsg{pod: pod, analysis: analysis}
+	// Only fail when no container could be read at all
+	var sources []taggedLogs
+	var firstErr error
+	for i, container := range containers {
+		if errs[i] != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("container %s: %w", container, errs[i])
+			}
+			continue
+		}
+		sources = append(sources, taggedLogs{tag: container, logs: outputs[i]})
+	}
+	if len(sources) == 0 && firstErr != nil {
+		return LogAnalysis{}, firstErr
+	}
+
+	return analyzeTimedLogs(sources, true, rules), nil
+}
+
+// fetchLogs reads a complete, non-following log stream, without the lines
+// logged after opts.Until
+func fetchLogs(ctx context.Context, client ClusterClient, namespace, pod string, opts LogOptions) (string, error) {
+	stream, err := client.StreamLogs(ctx, namespace, pod, opts)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	output, err := io.ReadAll(stream)
+	if err != nil {
+		return "", err
+	}
+	if !opts.Until.IsZero() && opts.Timestamps {
+		return trimUntil(string(output), opts.Until), nil
 	}
+	return string(output), nil
 }
 
 // Tick command for periodic updates
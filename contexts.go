@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"sigs.k8s.io/yaml"
+)
+
+// defaultProductionColor is the banner color of production contexts
+const defaultProductionColor = "#FF5F56"
+
+// ContextConfig marks kubeconfig contexts as production. Every view of a
+// production context shows a warning banner.
+type ContextConfig struct {
+	// Production lists glob patterns of production context names
+	Production []string `json:"production"`
+	// Color is the banner background color
+	Color string `json:"color,omitempty"`
+	// Banner replaces the default warning text
+	Banner string `json:"banner,omitempty"`
+
+	production []*regexp.Regexp
+}
+
+// DefaultContextConfig marks no context as production: guessing from the
+// name would also mark non-prod and preprod contexts
+func DefaultContextConfig() *ContextConfig {
+	return &ContextConfig{Color: defaultProductionColor}
+}
+
+// LoadContextConfig loads contexts.yaml (or .yml, .json) from the
+// configuration directory. A missing file selects the defaults.
+func LoadContextConfig() (*ContextConfig, error) {
+	config := DefaultContextConfig()
+
+	dir := configDir()
+	if dir == "" {
+		return config, nil
+	}
+	file := findConfigFile(filepath.Join(dir, "contexts"))
+	if file == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	if config.Color == "" {
+		config.Color = defaultProductionColor
+	}
+	if err := config.compile(); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return config, nil
+}
+
+// compile translates the production patterns into regular expressions once,
+// since every view checks its context. Blank patterns are rejected.
+func (c *ContextConfig) compile() error {
+	c.production = nil
+	for i, pattern := range c.Production {
+		if strings.TrimSpace(pattern) == "" {
+			return fmt.Errorf("production pattern %d is empty", i+1)
+		}
+		re, err := globRegexp(pattern)
+		if err != nil {
+			return fmt.Errorf("production pattern %q: %w", pattern, err)
+		}
+		c.production = append(c.production, re)
+	}
+	return nil
+}
+
+// IsProduction reports whether a context is marked as production
+func (c *ContextConfig) IsProduction(name string) bool {
+	if c == nil || name == "" {
+		return false
+	}
+	for _, re := range c.production {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// globRegexp translates a context name glob into a regular expression. Unlike
+// path.Match, * and ? also match "/", which EKS and GKE context names such
+// as arn:aws:eks:eu-west-1:123456789012:cluster/prod-eu are full of. Every
+// other character matches itself.
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	quoted := regexp.QuoteMeta(pattern)
+	quoted = strings.ReplaceAll(quoted, `\*`, ".*")
+	quoted = strings.ReplaceAll(quoted, `\?`, ".")
+	return regexp.Compile("^" + quoted + "$")
+}
+
+// style returns the text style of production context names
+func (c *ContextConfig) style() lipgloss.Style {
+	return lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(c.Color))
+}
+
+// ListContexts returns the sorted context names of a kubeconfig and its
+// current context. An empty path follows the default loading rules.
+func ListContexts(kubeconfig string) ([]string, string, error) {
+	raw, err := KubeOptions{Kubeconfig: kubeconfig}.clientConfig().RawConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("loading kubeconfig: %w", err)
+	}
+
+	contexts := make([]string, 0, len(raw.Contexts))
+	for name := range raw.Contexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+
+	return contexts, raw.CurrentContext, nil
+}
+
+// openContextPicker switches to the context picker and loads the contexts
+func (m *Model) openContextPicker() tea.Cmd {
+	m.currentView = "contexts"
+	m.loading = true
+	return LoadContexts(m.kubeOptions.Kubeconfig)
+}
+
+// closeContextPicker goes back to the namespaces of the active context
+func (m *Model) closeContextPicker() tea.Cmd {
+	m.currentView = "namespaces"
+	m.err = nil
+	m.loading = true
+	return m.loadNamespaces()
+}
+
+// switchContext drops everything loaded from the previous clusters, leaving
+// fan-out mode, and starts over at the namespace list of the new context
+func (m *Model) switchContext(msg SwitchContextMsg) tea.Cmd {
+	m.stopFollow()
+	m.client = msg.client
+	m.kubeContext = msg.context
+	m.clusters = nil
+	m.clusterErrs = nil
+	m.namespace = ""
+	m.namespaces = nil
+	m.loadedNS = nil
+	m.nsQuery = ""
+	m.pods = nil
+	m.allPods = nil
+	m.podQuery = ""
+	m.podErrors = nil
+	m.workload = ""
+	m.selectedNS = 0
+	m.selectedPod = 0
+	m.pageOffset = 0
+	m.logs = make(map[string]LogAnalysis)
+	m.previousLogs = make(map[string]LogAnalysis)
+	m.events = make(map[string]targetEvents)
+	m.diffBase = nil
+	m.diff = nil
+	m.refreshFails = 0
+	m.lastRefresh = time.Time{}
+	m.currentView = "namespaces"
+	m.loading = true
+	return m.loadNamespaces()
+}
+
+// productionBanner returns the warning shown above every view of a
+// production context, or "" when no production context is active
+func (m Model) productionBanner() string {
+	var production []string
+	for _, name := range m.activeContexts() {
+		if m.contextConf.IsProduction(name) {
+			production = append(production, name)
+		}
+	}
+	if len(production) == 0 {
+		return ""
+	}
+	text := m.contextConf.Banner
+	if text == "" {
+		text = m.localization.ProductionWarning
+	}
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#FAFAFA")).
+		Background(lipgloss.Color(m.contextConf.Color)).
+		Padding(0, 1).
+		Render("⚠ " + text + ": " + strings.Join(production, ", "))
+}
+
+// viewTitle renders a view title followed by the active contexts, below the
+// production banner when there is one
+func (m Model) viewTitle(text string) string {
+	title := TitleStyle.Render(text)
+	for _, name := range m.activeContexts() {
+		title += " " + m.contextStyle(name).Render("⎈ "+name)
+	}
+	if banner := m.productionBanner(); banner != "" {
+		title = banner + "\n\n" + title
+	}
+	return title
+}
+
+// contextStyle returns the style of a context name, highlighted for
+// production contexts
+func (m Model) contextStyle(name string) lipgloss.Style {
+	if m.contextConf.IsProduction(name) {
+		return m.contextConf.style()
+	}
+	return NormalStyle
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsProduction(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		context  string
+		want     bool
+	}{
+		{"no default", nil, "prod-eu", false},
+		{"empty name", []string{"*"}, "", false},
+		{"exact", []string{"prod"}, "prod", true},
+		{"exact not a substring", []string{"prod"}, "non-prod", false},
+		{"prefix not preprod", []string{"prod-*"}, "preprod-eu", false},
+		{"EKS ARN", []string{"*/prod-*"}, "arn:aws:eks:eu-west-1:123456789012:cluster/prod-eu", true},
+		{"GKE name", []string{"gke_*_prod"}, "gke_proj_europe-west1_prod", true},
+		{"prefix", []string{"prod-*"}, "prod-us", true},
+		{"prefix not in ARN", []string{"prod-*"}, "arn:aws:eks:eu-west-1:123456789012:cluster/prod-eu", false},
+		{"ARN suffix", []string{"*:cluster/prod-*"}, "arn:aws:eks:eu-west-1:123456789012:cluster/prod-eu", true},
+		{"question mark", []string{"prod-??"}, "prod-eu", true},
+		{"question mark too short", []string{"prod-??"}, "prod-e", false},
+		{"question mark matches slash", []string{"a?b"}, "a/b", true},
+		{"regexp characters are literal", []string{"prod.eu"}, "prod-eu", false},
+		{"brackets are literal", []string{"[prod]*"}, "[prod]-eu", true},
+		{"any pattern", []string{"dev", "*-live"}, "shop-live", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultContextConfig()
+			config.Production = tt.patterns
+			if err := config.compile(); err != nil {
+				t.Fatal(err)
+			}
+			if got := config.IsProduction(tt.context); got != tt.want {
+				t.Errorf("IsProduction(%q) with %q = %v, want %v", tt.context, config.Production, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadContextConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"valid", "production:\n  - prod-*\ncolor: '#00FF00'\n", ""},
+		{"blank pattern", "production:\n  - prod-*\n  - ''\n", "production pattern 2 is empty"},
+		{"unknown field", "prod:\n  - prod-*\n", "unknown field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", dir)
+			config := filepath.Join(dir, "k8s-pod-log-analyzer")
+			if err := os.MkdirAll(config, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(config, "contexts.yaml"), []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := LoadContextConfig()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !loaded.IsProduction("prod-eu") || loaded.IsProduction("preprod-eu") {
+				t.Errorf("patterns %q not applied", loaded.Production)
+			}
+		})
+	}
+}
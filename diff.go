@@ -0,0 +1,359 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// defaultDiffWindow is the window compared with the one before it when
+	// a pod is diffed with itself and the time range has no start
+	defaultDiffWindow = time.Hour
+	// minDiffDelta is the smallest change in lines of a signature listed as
+	// changed, so rare errors do not show up for every single line
+	minDiffDelta = 3
+	// maxDiffRows is the number of signatures listed per section of a pane
+	maxDiffRows = 10
+)
+
+// Kinds of signature differences
+const (
+	DiffNew     = "new"
+	DiffGone    = "gone"
+	DiffChanged = "changed"
+)
+
+// diffSide is one of the two inputs of a diff: the logs of a pod within a
+// time range
+type diffSide struct {
+	client   ClusterClient
+	target   logTarget
+	label    string
+	window   timeRange
+	analysis LogAnalysis
+}
+
+// podDiff compares the error signatures of two analyses
+type podDiff struct {
+	base    PodInfo // Pods diffed, the same one to compare time windows
+	other   PodInfo
+	before  diffSide
+	after   diffSide
+	changes []signatureDiff
+}
+
+// signatureDiff is an error signature that is new, gone or changed
+// significantly in frequency between two analyses
+type signatureDiff struct {
+	Kind     string
+	Template string
+	Before   int
+	After    int
+}
+
+// LoadDiff command to analyze both inputs of a diff concurrently and
+// compare their error signatures
+func LoadDiff(namespace string, diff podDiff, rules *RuleSet) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		sides := []*diffSide{&diff.before, &diff.after}
+		errs := make([]error, len(sides))
+		var wg sync.WaitGroup
+		for i, side := range sides {
+			wg.Add(1)
+			go func(i int, side *diffSide) {
+				defer wg.Done()
+				side.analysis, errs[i] = loadDiffSide(ctx, namespace, *side, rules)
+				if errs[i] != nil {
+					errs[i] = fmt.Errorf("%s: %w", side.label, errs[i])
+				}
+			}(i, side)
+		}
+		wg.Wait()
+
+		if err := errors.Join(errs...); err != nil {
+			return DiffMsg{err: err}
+		}
+		diff.changes = diffSignatures(diff.before.analysis, diff.after.analysis)
+		return DiffMsg{diff: diff}
+	}
+}
+
+// loadDiffSide fetches and analyzes the logs of every container of a diff
+// input within its time range
+func loadDiffSide(ctx context.Context, namespace string, side diffSide, rules *RuleSet) (LogAnalysis, error) {
+	opts := side.window.logOptions()
+	opts.Timestamps = true
+
+	streams := side.target.streams()
+	var sources []taggedLogs
+	for _, stream := range streams {
+		opts.Container = stream.container
+		output, err := fetchLogs(ctx, side.client, namespace, stream.pod, opts)
+		if err != nil {
+			return LogAnalysis{}, err
+		}
+		sources = append(sources, taggedLogs{tag: stream.tag, logs: output})
+	}
+
+	return analyzeTimedLogs(sources, len(streams) > 1, rules), nil
+}
+
+// diffSignatures pairs the error signatures of two analyses and returns
+// those that are new, gone or changed significantly in frequency: changed
+// ones first, each kind with the largest difference first
+func diffSignatures(before, after LogAnalysis) []signatureDiff {
+	var changes []signatureDiff
+	paired := make([]bool, len(after.Signatures))
+	for _, old := range before.Signatures {
+		match, best := -1, 0.0
+		for i, sig := range after.Signatures {
+			if paired[i] || len(sig.tokens) != len(old.tokens) {
+				continue
+			}
+			// Either template may have the wildcards
+			score := tokenSimilarity(old.tokens, sig.tokens)
+			if reverse := tokenSimilarity(sig.tokens, old.tokens); reverse > score {
+				score = reverse
+			}
+			if score > best {
+				match, best = i, score
+			}
+		}
+
+		if match < 0 || best < signatureSimilarity {
+			changes = append(changes, signatureDiff{Kind: DiffGone, Template: old.Template, Before: old.Count})
+			continue
+		}
+		paired[match] = true
+		sig := after.Signatures[match]
+		if significantChange(old.Count, sig.Count) {
+			changes = append(changes, signatureDiff{Kind: DiffChanged, Template: sig.Template, Before: old.Count, After: sig.Count})
+		}
+	}
+	for i, sig := range after.Signatures {
+		if !paired[i] {
+			changes = append(changes, signatureDiff{Kind: DiffNew, Template: sig.Template, After: sig.Count})
+		}
+	}
+
+	order := map[string]int{DiffChanged: 0, DiffNew: 1, DiffGone: 2}
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return order[changes[i].Kind] < order[changes[j].Kind]
+		}
+		return changes[i].delta() > changes[j].delta()
+	})
+	return changes
+}
+
+// delta returns by how many lines a signature changed
+func (d signatureDiff) delta() int {
+	if d.After > d.Before {
+		return d.After - d.Before
+	}
+	return d.Before - d.After
+}
+
+// significantChange reports whether the count of a signature at least
+// doubled or halved, by at least minDiffDelta lines
+func significantChange(before, after int) bool {
+	d := signatureDiff{Before: before, After: after}
+	return d.delta() >= minDiffDelta && (after >= 2*before || before >= 2*after)
+}
+
+// markDiff marks the selected pod as the first input of a diff, or diffs
+// it with the marked pod. Diffing the marked pod with itself compares the
+// analyzed time range with the window as long before it, until b picks
+// another one.
+func (m *Model) markDiff() tea.Cmd {
+	pod, ok := m.selected()
+	if !ok || m.onGroup {
+		return nil
+	}
+	if m.diffBase == nil {
+		m.diffBase = &pod
+		return nil
+	}
+
+	base := *m.diffBase
+	m.diffBase = nil
+	m.baseRange = nil
+	m.loading = true
+	return m.loadDiff(base, pod)
+}
+
+// loadDiff returns the command diffing two pods, or the last two windows of
+// one pod. The base side covers the window picked with b when there is one.
+func (m Model) loadDiff(base, other PodInfo) tea.Cmd {
+	diff := podDiff{
+		base:   base,
+		other:  other,
+		before: diffSide{client: m.clientFor(base.Context), target: logTargetFor(base, AllContainers), label: podLabel(base), window: m.window},
+		after:  diffSide{client: m.clientFor(other.Context), target: logTargetFor(other, AllContainers), label: podLabel(other), window: m.window},
+	}
+	now := time.Now()
+	if base.Name == other.Name && base.Context == other.Context {
+		end, window := m.window.end(now), defaultDiffWindow
+		if start := m.window.start(now); !start.IsZero() {
+			window = end.Sub(start)
+		}
+		diff.before.window = timeRange{from: end.Add(-2 * window), until: end.Add(-window)}
+		diff.after.window = timeRange{from: end.Add(-window), until: m.window.until}
+	}
+	if m.baseRange != nil {
+		diff.before.window = *m.baseRange
+	}
+	if diff.before.window != diff.after.window {
+		diff.before.label += " " + m.windowLabel(diff.before.window, now)
+		diff.after.label += " " + m.windowLabel(diff.after.window, now)
+	}
+	return LoadDiff(m.namespace, diff, m.rules.ForNamespace(m.namespace))
+}
+
+// windowLabel formats the time range of a diff input, such as
+// (14:00 - now)
+func (m Model) windowLabel(r timeRange, now time.Time) string {
+	from, to := "…", m.localization.Now
+	if start := r.start(now); !start.IsZero() {
+		from = start.Format("15:04")
+	}
+	if r.closed() {
+		to = r.until.Format("15:04")
+	}
+	return fmt.Sprintf("(%s - %s)", from, to)
+}
+
+// setBaseRange compares the diff with the base side in another time range
+func (m *Model) setBaseRange(r timeRange) tea.Cmd {
+	m.picking = false
+	m.pickErr = nil
+	m.baseRange = &r
+	m.loading = true
+	return m.loadDiff(m.diff.base, m.diff.other)
+}
+
+// podLabel names a pod, with its cluster in fan-out mode
+func podLabel(pod PodInfo) string {
+	if pod.Context != "" {
+		return pod.Context + "/" + pod.Name
+	}
+	return pod.Name
+}
+
+// diffStatus tells which pod is marked for a diff, or "" when none is
+func (m Model) diffStatus() string {
+	if m.diffBase == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s: %s %s\n\n", m.localization.Diff,
+		SelectedStyle.Render(podLabel(*m.diffBase)), NormalStyle.Render(m.localization.DiffHelp))
+}
+
+// RenderDiffView renders the two inputs of a diff in a split pane: changed
+// signatures side by side, then the gone ones left and the new ones right
+func (m Model) RenderDiffView() string {
+	diff := m.diff
+	title := m.viewTitle(fmt.Sprintf("%s: %s ⇄ %s", m.localization.Diff, diff.before.label, diff.after.label))
+
+	var content strings.Builder
+	content.WriteString(title + "\n\n")
+	content.WriteString(m.timeStatus())
+
+	width := max(30, (m.width-13)/2)
+	left := []string{m.renderDiffHeader(diff.before, width)}
+	right := []string{m.renderDiffHeader(diff.after, width)}
+
+	var changed, gone, added []signatureDiff
+	for _, change := range diff.changes {
+		switch change.Kind {
+		case DiffChanged:
+			changed = append(changed, change)
+		case DiffGone:
+			gone = append(gone, change)
+		case DiffNew:
+			added = append(added, change)
+		}
+	}
+
+	if len(changed) > 0 {
+		left = append(left, m.renderDiffSection(m.localization.DiffChanged, changed, width, false))
+		right = append(right, m.renderDiffSection(m.localization.DiffChanged, changed, width, true))
+	}
+	// Equal heights keep the changed signatures of both panes side by side
+	if len(gone) > 0 {
+		left = append(left, m.renderDiffSection(m.localization.DiffGone, gone, width, false))
+	}
+	if len(added) > 0 {
+		right = append(right, m.renderDiffSection(m.localization.DiffNew, added, width, true))
+	}
+
+	pane := lipgloss.NewStyle().Width(width)
+	leftPane, rightPane := pane.Render(strings.Join(left, "\n")), pane.Render(strings.Join(right, "\n"))
+	height := max(lipgloss.Height(leftPane), lipgloss.Height(rightPane))
+	separator := strings.TrimSuffix(strings.Repeat(" │ \n", height), "\n")
+	content.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, leftPane, separator, rightPane) + "\n\n")
+	if len(diff.changes) == 0 {
+		content.WriteString(SuccessStyle.Render(m.localization.NoDifferences) + "\n\n")
+	}
+
+	content.WriteString(m.localization.Controls + ":\n")
+	content.WriteString("  " + m.localization.GoBack + "\n")
+	content.WriteString("  " + m.localization.RefreshLogs + "\n")
+	content.WriteString("  " + m.localization.PickTime + "\n")
+	content.WriteString("  " + m.localization.PickBaseTime + "\n")
+	content.WriteString("  " + m.localization.Exit)
+
+	return BorderStyle.Render(content.String())
+}
+
+// renderDiffHeader names one input of a diff with its line counts
+func (m Model) renderDiffHeader(side diffSide, width int) string {
+	analysis := side.analysis
+	return fmt.Sprintf("%s\n%s: %s  %s: %s  %s: %s\n",
+		SelectedStyle.Render(m.truncateLogLine(side.label, width)),
+		m.localization.Lines, InfoStyle.Render(strconv.Itoa(analysis.TotalLines)),
+		m.localization.Errors, ErrorStyle.Render(strconv.Itoa(analysis.ErrorCount)),
+		m.localization.Warnings, WarningStyle.Render(strconv.Itoa(analysis.WarningCount)))
+}
+
+// renderDiffSection lists signature differences with their count on one
+// side of the diff; the after side also shows how the count changed
+func (m Model) renderDiffSection(label string, changes []signatureDiff, width int, after bool) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s (%d):\n", label, len(changes)))
+	for _, change := range changes[:min(maxDiffRows, len(changes))] {
+		count, style := change.Before, NormalStyle
+		trend := ""
+		if after {
+			count = change.After
+			switch {
+			case change.Kind == DiffNew:
+				style, trend = ErrorStyle, "+"
+			case change.After > change.Before:
+				style, trend = ErrorStyle, fmt.Sprintf("↑×%.1f", float64(change.After)/float64(change.Before))
+			default:
+				style, trend = SuccessStyle, fmt.Sprintf("↓×%.1f", float64(change.After)/float64(change.Before))
+			}
+		} else if change.Kind == DiffGone {
+			style = SuccessStyle
+		}
+		prefix := fmt.Sprintf("  %6d %-7s ", count, trend)
+		b.WriteString(style.Render(prefix) + m.truncateLogLine(change.Template, max(10, width-len(prefix))) + "\n")
+	}
+	if more := len(changes) - maxDiffRows; more > 0 {
+		b.WriteString(NormalStyle.Render(fmt.Sprintf("  +%d", more)) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignificantChange(t *testing.T) {
+	tests := []struct {
+		before, after int
+		want          bool
+	}{
+		{0, 3, true},
+		{0, 2, false},
+		{3, 6, true},
+		{3, 5, false},
+		{10, 20, true},
+		{10, 19, false},
+		{8, 4, true},
+		{4, 2, false},
+		{5, 5, false},
+	}
+	for _, tt := range tests {
+		if got := significantChange(tt.before, tt.after); got != tt.want {
+			t.Errorf("significantChange(%d, %d) = %v, want %v", tt.before, tt.after, got, tt.want)
+		}
+	}
+}
+
+func TestDiffSignatures(t *testing.T) {
+	repeat := func(line string, n int) string {
+		return strings.Repeat(line+"\n", n)
+	}
+	before := AnalyzeLogs(repeat("ERROR cache miss", 5)+
+		repeat("ERROR disk full", 1)+
+		repeat("ERROR quota exceeded", 2)+
+		repeat("ERROR user alice not found", 1)+
+		repeat("ERROR user bob not found", 1), nil)
+	after := AnalyzeLogs(repeat("ERROR cache miss", 1)+
+		repeat("ERROR quota exceeded", 3)+
+		repeat("ERROR user carol not found", 2)+
+		repeat("ERROR tls handshake failed", 4)+
+		repeat("ERROR connection reset by peer", 3), nil)
+
+	want := []signatureDiff{
+		{Kind: DiffChanged, Template: "ERROR cache miss", Before: 5, After: 1},
+		{Kind: DiffNew, Template: "ERROR tls handshake failed", After: 4},
+		{Kind: DiffNew, Template: "ERROR connection reset by peer", After: 3},
+		{Kind: DiffGone, Template: "ERROR disk full", Before: 1},
+	}
+	got := diffSignatures(before, after)
+	if len(got) != len(want) {
+		t.Fatalf("changes = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if changes := diffSignatures(before, before); len(changes) != 0 {
+		t.Errorf("diff of an analysis with itself = %+v, want none", changes)
+	}
+}
+
+func TestLoadDiffWindows(t *testing.T) {
+	client := NewFakeClient()
+	client.AddPod(testPod("api", 0, "app"))
+	client.AddPod(testPod("web", 0, "app"))
+	client.SetLogs("ns", "api", "app", false, "ERROR boom\n")
+	client.SetLogs("ns", "web", "app", false, "ERROR boom\n")
+
+	m := loadedModel(t, client)
+	m.window = timeRange{since: 30 * time.Minute}
+	api, web := m.pods[0], m.pods[1]
+	base := timeRange{from: time.Now().Add(-24 * time.Hour), until: time.Now().Add(-23 * time.Hour)}
+
+	tests := []struct {
+		name       string
+		other      PodInfo
+		baseRange  *timeRange
+		wantBefore func(r timeRange) bool
+		wantLabels bool // Whether the labels carry the windows
+	}{
+		{
+			name:       "two pods in the same window",
+			other:      web,
+			wantBefore: func(r timeRange) bool { return r == m.window },
+		},
+		{
+			name:  "a pod with itself compares the window before",
+			other: api,
+			wantBefore: func(r timeRange) bool {
+				return r.until.Sub(r.from) == 30*time.Minute && time.Since(r.until) >= 30*time.Minute
+			},
+			wantLabels: true,
+		},
+		{
+			name:       "a picked base window",
+			other:      api,
+			baseRange:  &base,
+			wantBefore: func(r timeRange) bool { return r == base },
+			wantLabels: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.baseRange = tt.baseRange
+			msg, ok := m.loadDiff(api, tt.other)().(DiffMsg)
+			if !ok || msg.err != nil {
+				t.Fatalf("diff not loaded: %v", msg.err)
+			}
+
+			diff := msg.diff
+			if !tt.wantBefore(diff.before.window) {
+				t.Errorf("base window = %+v", diff.before.window)
+			}
+			if hasWindow := strings.Contains(diff.before.label, "("); hasWindow != tt.wantLabels {
+				t.Errorf("labels %q and %q", diff.before.label, diff.after.label)
+			}
+			if diff.before.analysis.ErrorCount+diff.after.analysis.ErrorCount == 0 {
+				t.Error("neither side was analyzed")
+			}
+		})
+	}
+}
+
+func TestLoadDiffError(t *testing.T) {
+	client := NewFakeClient()
+	client.AddPod(testPod("api", 0, "app"))
+	client.SetLogs("ns", "api", "app", false, "ERROR boom\n")
+
+	diff := podDiff{
+		before: diffSide{client: client, target: logTarget{pod: "api", containers: []string{"app"}}, label: "api"},
+		after:  diffSide{client: client, target: logTarget{pod: "gone", containers: []string{"app"}}, label: "gone"},
+	}
+	msg := LoadDiff("ns", diff, nil)().(DiffMsg)
+	if msg.err == nil || !strings.HasPrefix(msg.err.Error(), "gone: ") {
+		t.Errorf("err = %v, want one of the gone side", msg.err)
+	}
+}
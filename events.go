@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxPanelEvents is the number of most recent events listed in the events
+// panel
+const maxPanelEvents = 8
+
+// minEventPanelWidth is the narrowest events panel shown beside the pod
+// details; a narrower terminal shows it below them
+const minEventPanelWidth = 60
+
+// maxEventQueries bounds the events listed object by object; more objects,
+// such as the replicas of a workload, list the whole namespace once instead
+const maxEventQueries = 3
+
+// PodEvent is a Kubernetes event about an analyzed pod or its workload
+type PodEvent struct {
+	Type    string // Normal or Warning
+	Reason  string
+	Message string
+	Object  string // Involved object as Kind/name
+	Count   int
+	Last    time.Time // Last occurrence
+}
+
+// targetEvents are the events of an analyzed target. err is set when they
+// could not be listed, for example without permission to list events.
+type targetEvents struct {
+	events []PodEvent // Newest first
+	err    error
+}
+
+// newPodEvent converts a Kubernetes event, which carries its time and count
+// in different fields depending on the API that recorded it
+func newPodEvent(event corev1.Event) PodEvent {
+	last := event.LastTimestamp.Time
+	if last.IsZero() {
+		last = event.EventTime.Time
+	}
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		last = event.Series.LastObservedTime.Time
+	}
+	if last.IsZero() {
+		last = event.FirstTimestamp.Time
+	}
+	if last.IsZero() {
+		last = event.CreationTimestamp.Time
+	}
+
+	count := int(event.Count)
+	if event.Series != nil {
+		count = int(event.Series.Count)
+	}
+
+	return PodEvent{
+		Type:    event.Type,
+		Reason:  event.Reason,
+		Message: strings.TrimSpace(event.Message),
+		Object:  event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+		Count:   max(1, count),
+		Last:    last,
+	}
+}
+
+// loadEvents lists the events of the given objects, newest first
+func loadEvents(ctx context.Context, client ClusterClient, namespace string, objects []string) targetEvents {
+	wanted := make(map[string]bool, len(objects))
+	var names []string
+	for _, object := range objects {
+		wanted[object] = true
+		_, name, _ := strings.Cut(object, "/")
+		names = append(names, name)
+	}
+	if len(names) > maxEventQueries {
+		names = []string{""}
+	}
+
+	var events []PodEvent
+	for _, name := range names {
+		items, err := client.ListEvents(ctx, namespace, name)
+		if err != nil {
+			return targetEvents{err: err}
+		}
+		for _, item := range items {
+			if event := newPodEvent(item); wanted[event.Object] {
+				events = append(events, event)
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Last.After(events[j].Last) })
+	return targetEvents{events: events}
+}
+
+// eventObjects returns the objects whose events are shown with the analysis
+// of a target: its pods and the workloads owning them
+func (m Model) eventObjects(target logTarget) []string {
+	pods := m.workloadPods()
+	if target.workload == "" {
+		pods = nil
+		if pod, ok := m.selected(); ok {
+			pods = append(pods, pod)
+		}
+	}
+
+	var objects []string
+	seen := make(map[string]bool)
+	for _, pod := range pods {
+		for _, object := range []string{"Pod/" + pod.Name, pod.Workload} {
+			if object != "" && !seen[object] {
+				seen[object] = true
+				objects = append(objects, object)
+			}
+		}
+	}
+	return objects
+}
+
+// setEvents stores the events of a target. A failed refresh keeps the
+// events listed before.
+func (m *Model) setEvents(key string, events targetEvents) {
+	if m.events == nil {
+		m.events = make(map[string]targetEvents)
+	}
+	if events.err != nil {
+		events.events = m.events[key].events
+	}
+	m.events[key] = events
+}
+
+// eventMessage returns the message of an event, prefixed with its object
+// unless it is the analyzed pod
+func eventMessage(event PodEvent, target logTarget) string {
+	if target.workload == "" && event.Object == "Pod/"+target.pod {
+		return event.Message
+	}
+	return event.Object + ": " + event.Message
+}
+
+// renderEventsPanel lists the most recent events of the analyzed target with
+// their type, reason, count and age
+func (m Model) renderEventsPanel(target logTarget, width int) string {
+	events := m.events[target.key()]
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s (%d):\n", m.localization.Events, len(events.events)))
+	if events.err != nil {
+		b.WriteString("  " + ErrorStyle.Render(m.truncateLogLine(events.err.Error(), width-2)) + "\n")
+	}
+	if len(events.events) == 0 {
+		if events.err == nil {
+			b.WriteString("  " + NormalStyle.Render(m.localization.NoEvents) + "\n")
+		}
+		return b.String()
+	}
+
+	b.WriteString(NormalStyle.Render(fmt.Sprintf("  %-8s %-20s %6s %5s  %s",
+		m.localization.EventType, m.localization.Reason, m.localization.Count, m.localization.Age, m.localization.Message)) + "\n")
+	for _, event := range events.events[:min(maxPanelEvents, len(events.events))] {
+		style := NormalStyle
+		if event.Type == corev1.EventTypeWarning {
+			style = WarningStyle
+		}
+		b.WriteString(fmt.Sprintf("  %s %-20s %6s %5s  %s\n",
+			style.Render(fmt.Sprintf("%-8s", event.Type)),
+			m.truncateLogLine(event.Reason, 20),
+			"×"+strconv.Itoa(event.Count),
+			CalculateAge(event.Last),
+			m.truncateLogLine(eventMessage(event, target), max(10, width-48)),
+		))
+	}
+	if more := len(events.events) - maxPanelEvents; more > 0 {
+		b.WriteString(NormalStyle.Render(fmt.Sprintf("  +%d", more)) + "\n")
+	}
+	return b.String()
+}
+
+// eventSlots places the Warning events of a target on the timeline of its
+// log: each event is keyed by the index of the first entry logged after it,
+// or by the number of entries when it is newer than every line. Lines
+// without a time share the time of the line before them; a log without any
+// time gets no events.
+func (m Model) eventSlots(analysis LogAnalysis, target logTarget) map[int][]PodEvent {
+	events := m.events[target.key()].events
+	times, ok := analysis.entryTimes()
+	if !ok {
+		return nil
+	}
+
+	slots := make(map[int][]PodEvent)
+	// Oldest first, so events sharing a slot are in order
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		if event.Type != corev1.EventTypeWarning {
+			continue
+		}
+		slot := len(times)
+		for j, at := range times {
+			if at.After(event.Last) {
+				slot = j
+				break
+			}
+		}
+		slots[slot] = append(slots[slot], event)
+	}
+	return slots
+}
+
+// renderEventLine renders a Warning event between the log lines around it
+func (m Model) renderEventLine(event PodEvent, target logTarget) string {
+	text := fmt.Sprintf("%s %s ×%d  %s", event.Last.Local().Format("15:04:05"), event.Reason, event.Count, eventMessage(event, target))
+	return "      " + WarningStyle.Render("⚑ "+m.truncateLogLine(text, max(10, m.width-20)))
+}
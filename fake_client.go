@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// FakeClient is an in-memory ClusterClient used to drive the TUI without a
+// cluster. It is safe for concurrent use by tea commands.
+type FakeClient struct {
+	mu         sync.RWMutex
+	namespaces map[string]bool
+	pods       map[string][]corev1.Pod
+	logs       map[string][]fakeLogLine
+	changed    chan struct{} // Closed and replaced whenever logs are appended
+	events     map[string][]corev1.Event
+	replicas   map[string][]appsv1.ReplicaSet
+
+	// Err, when set, is returned by every call
+	Err error
+}
+
+// NewFakeClient returns an empty FakeClient
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		namespaces: make(map[string]bool),
+		pods:       make(map[string][]corev1.Pod),
+		logs:       make(map[string][]fakeLogLine),
+		changed:    make(chan struct{}),
+		events:     make(map[string][]corev1.Event),
+		replicas:   make(map[string][]appsv1.ReplicaSet),
+	}
+}
+
+// AddNamespace registers an empty namespace
+func (f *FakeClient) AddNamespace(namespace string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.namespaces[namespace] = true
+}
+
+// AddPod registers a pod in its namespace, creating the namespace if needed
+func (f *FakeClient) AddPod(pod corev1.Pod) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.namespaces[pod.Namespace] = true
+	f.pods[pod.Namespace] = append(f.pods[pod.Namespace], pod)
+}
+
+// fakeLogLine is a line of a fake container log and when it was logged
+type fakeLogLine struct {
+	at   time.Time
+	text string
+}
+
+// SetLogs sets the log output returned for a pod container. Lines may start
+// with an RFC3339 timestamp, as the API prepends them; lines without one
+// are logged along with the previous line, or now.
+func (f *FakeClient) SetLogs(namespace, pod, container string, previous bool, logs string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.logs[fakeLogKey(namespace, pod, container, previous)] = parseFakeLogs(logs, time.Now())
+}
+
+// AppendLogs adds lines to the current log of a pod container, the way
+// SetLogs reads them, and hands them to the streams following it
+func (f *FakeClient) AppendLogs(namespace, pod, container, logs string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := fakeLogKey(namespace, pod, container, false)
+	f.logs[key] = append(f.logs[key], parseFakeLogs(logs, time.Now())...)
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// parseFakeLogs splits a log output into timed lines
+func parseFakeLogs(logs string, now time.Time) []fakeLogLine {
+	if logs == "" {
+		return nil
+	}
+	var lines []fakeLogLine
+	last := now
+	for _, line := range strings.Split(strings.TrimSuffix(logs, "\n"), "\n") {
+		at, text, ok := splitTimestamp(line)
+		if ok {
+			last = at
+		}
+		lines = append(lines, fakeLogLine{at: last, text: text})
+	}
+	return lines
+}
+
+// AddEvent registers an event in its namespace
+func (f *FakeClient) AddEvent(event corev1.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events[event.Namespace] = append(f.events[event.Namespace], event)
+}
+
+// AddReplicaSet registers a replica set in its namespace
+func (f *FakeClient) AddReplicaSet(rs appsv1.ReplicaSet) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.replicas[rs.Namespace] = append(f.replicas[rs.Namespace], rs)
+}
+
+// ListNamespaces implements ClusterClient
+func (f *FakeClient) ListNamespaces(ctx context.Context) ([]string, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	namespaces := make([]string, 0, len(f.namespaces))
+	for ns := range f.namespaces {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	return namespaces, nil
+}
+
+// ListPods implements ClusterClient
+func (f *FakeClient) ListPods(ctx context.Context, namespace string, opts PodListOptions) ([]corev1.Pod, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	if !f.namespaces[namespace] {
+		return nil, fmt.Errorf("namespaces %q not found", namespace)
+	}
+
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	fieldSelector, err := fields.ParseSelector(opts.FieldSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []corev1.Pod
+	for _, pod := range f.pods[namespace] {
+		if selector.Matches(labels.Set(pod.Labels)) && fieldSelector.Matches(podFields(pod)) {
+			pods = append(pods, pod)
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	return pods, nil
+}
+
+// podFields returns the fields a pod can be selected by, as the API server
+// supports them
+func podFields(pod corev1.Pod) fields.Set {
+	return fields.Set{
+		"metadata.name":            pod.Name,
+		"metadata.namespace":       pod.Namespace,
+		"spec.nodeName":            pod.Spec.NodeName,
+		"spec.restartPolicy":       string(pod.Spec.RestartPolicy),
+		"spec.schedulerName":       pod.Spec.SchedulerName,
+		"spec.serviceAccountName":  pod.Spec.ServiceAccountName,
+		"status.phase":             string(pod.Status.Phase),
+		"status.podIP":             pod.Status.PodIP,
+		"status.nominatedNodeName": pod.Status.NominatedNodeName,
+	}
+}
+
+// StreamLogs implements ClusterClient. Like the API, it truncates
+// SinceTime to whole seconds, prepends timestamps on request and, when
+// following, keeps the stream open for the lines appended later.
+func (f *FakeClient) StreamLogs(ctx context.Context, namespace, pod string, opts LogOptions) (io.ReadCloser, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	key := fakeLogKey(namespace, pod, opts.Container, opts.Previous)
+	lines, ok := f.logs[key]
+	if !ok {
+		return nil, fmt.Errorf("pods %q not found", pod)
+	}
+
+	since := opts.SinceTime.Truncate(time.Second)
+	if opts.Since > 0 {
+		since = time.Now().Add(-opts.Since)
+	}
+	if !opts.Follow {
+		return io.NopCloser(strings.NewReader(formatFakeLogs(lines, since, opts.Timestamps))), nil
+	}
+
+	reader, writer := io.Pipe()
+	go f.follow(ctx, key, since, opts.Timestamps, writer)
+	return reader, nil
+}
+
+// follow writes the lines of a log to a follow stream as they are appended,
+// until the context is done
+func (f *FakeClient) follow(ctx context.Context, key string, since time.Time, timestamps bool, w *io.PipeWriter) {
+	sent := 0
+	for {
+		f.mu.RLock()
+		lines, changed := f.logs[key], f.changed
+		f.mu.RUnlock()
+
+		if sent < len(lines) {
+			if _, err := io.WriteString(w, formatFakeLogs(lines[sent:], since, timestamps)); err != nil {
+				return
+			}
+			sent = len(lines)
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			w.CloseWithError(ctx.Err())
+			return
+		}
+	}
+}
+
+// formatFakeLogs renders the lines logged at or after since, with their
+// timestamps when requested
+func formatFakeLogs(lines []fakeLogLine, since time.Time, timestamps bool) string {
+	var b strings.Builder
+	for _, line := range lines {
+		if line.at.Before(since) {
+			continue
+		}
+		if timestamps {
+			b.WriteString(line.at.Format(time.RFC3339Nano) + " ")
+		}
+		b.WriteString(line.text + "\n")
+	}
+	return b.String()
+}
+
+// ListEvents implements ClusterClient
+func (f *FakeClient) ListEvents(ctx context.Context, namespace, object string) ([]corev1.Event, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	var events []corev1.Event
+	for _, event := range f.events[namespace] {
+		if object == "" || event.InvolvedObject.Name == object {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// ListReplicaSets implements ClusterClient
+func (f *FakeClient) ListReplicaSets(ctx context.Context, namespace string) ([]appsv1.ReplicaSet, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	return f.replicas[namespace], nil
+}
+
+func fakeLogKey(namespace, pod, container string, previous bool) string {
+	return fmt.Sprintf("%s/%s/%s/%t", namespace, pod, container, previous)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFakeClientStreamLogs(t *testing.T) {
+	f := NewFakeClient()
+	f.SetLogs("ns", "api", "app", false, "2024-05-01T10:00:00.2Z first\n"+
+		"2024-05-01T10:00:01.5Z second\n"+
+		"\tcontinued\n"+
+		"2024-05-01T10:00:03Z third\n")
+	f.SetLogs("ns", "api", "app", true, "2024-05-01T09:00:00Z crashed\n")
+	f.SetLogs("ns", "fresh", "app", false, "just logged\n")
+
+	tests := []struct {
+		name string
+		pod  string
+		opts LogOptions
+		want string
+	}{
+		{"everything", "api", LogOptions{Container: "app"},
+			"first\nsecond\n\tcontinued\nthird\n"},
+		{"timestamps", "api", LogOptions{Container: "app", Timestamps: true, SinceTime: time.Date(2024, 5, 1, 10, 0, 3, 0, time.UTC)},
+			"2024-05-01T10:00:03Z third\n"},
+		{"since time truncated to seconds", "api", LogOptions{Container: "app", SinceTime: time.Date(2024, 5, 1, 10, 0, 1, 900e6, time.UTC)},
+			"second\n\tcontinued\nthird\n"},
+		{"since drops old lines", "api", LogOptions{Container: "app", Since: time.Hour}, ""},
+		{"since keeps recent lines", "fresh", LogOptions{Container: "app", Since: time.Minute}, "just logged\n"},
+		{"previous instance", "api", LogOptions{Container: "app", Previous: true}, "crashed\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fetchLogs(context.Background(), f, "ns", tt.pod, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("logs = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := f.StreamLogs(context.Background(), "ns", "api", LogOptions{Container: "sidecar"}); err == nil {
+		t.Error("StreamLogs of an unknown container succeeded")
+	}
+}
+
+func TestFakeClientFollow(t *testing.T) {
+	f := NewFakeClient()
+	f.SetLogs("ns", "api", "app", false, "2024-05-01T10:00:00Z old\n2024-05-01T10:00:05Z current\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := f.StreamLogs(ctx, "ns", "api", LogOptions{
+		Container: "app",
+		SinceTime: time.Date(2024, 5, 1, 10, 0, 5, 0, time.UTC),
+		Follow:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	lines := make(chan string)
+	done := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(stream)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		done <- scanner.Err()
+	}()
+
+	next := func() string {
+		t.Helper()
+		select {
+		case line := <-lines:
+			return line
+		case <-time.After(time.Second):
+			t.Fatal("no line streamed")
+			return ""
+		}
+	}
+	if line := next(); line != "current" {
+		t.Errorf("first line = %q, want current", line)
+	}
+	f.AppendLogs("ns", "api", "app", "2024-05-01T10:00:06Z appended\n")
+	if line := next(); line != "appended" {
+		t.Errorf("appended line = %q, want appended", line)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("stream ended with %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream still open after cancel")
+	}
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fanOutTimeout bounds scanning the logs of the listed pods
+const fanOutTimeout = 2 * time.Minute
+
+// cluster is one kubeconfig context of the fan-out mode
+type cluster struct {
+	context string
+	client  ClusterClient
+}
+
+// newClusters connects to every context of a comma separated list
+func newClusters(kubeconfig, contexts string) ([]cluster, error) {
+	var clusters []cluster
+	for _, name := range strings.Split(contexts, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		client, err := NewKubeClient(KubeOptions{Kubeconfig: kubeconfig, Context: name})
+		if err != nil {
+			return nil, fmt.Errorf("context %s: %w", name, err)
+		}
+		clusters = append(clusters, cluster{context: name, client: client})
+	}
+	if len(clusters) == 0 {
+		return nil, errors.New("no contexts given")
+	}
+	return clusters, nil
+}
+
+// LoadClusterNamespaces command to list the namespaces of every cluster
+// concurrently. The result is the sorted union; clusters that fail are
+// skipped unless all of them do.
+func LoadClusterNamespaces(clusters []cluster) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		lists := make([][]string, len(clusters))
+		errs := make([]error, len(clusters))
+		var wg sync.WaitGroup
+		for i, c := range clusters {
+			wg.Add(1)
+			go func(i int, c cluster) {
+				defer wg.Done()
+				lists[i], errs[i] = c.client.ListNamespaces(ctx)
+				if errs[i] != nil {
+					errs[i] = fmt.Errorf("%s: %w", c.context, errs[i])
+				}
+			}(i, c)
+		}
+		wg.Wait()
+
+		seen := make(map[string]bool)
+		var namespaces []string
+		failed := 0
+		for i, list := range lists {
+			if errs[i] != nil {
+				failed++
+			}
+			for _, ns := range list {
+				if !seen[ns] {
+					seen[ns] = true
+					namespaces = append(namespaces, ns)
+				}
+			}
+		}
+		if failed == len(clusters) {
+			return LoadNamespacesMsg{err: errors.Join(errs...)}
+		}
+		sort.Strings(namespaces)
+
+		return LoadNamespacesMsg{namespaces: namespaces}
+	}
+}
+
+// LoadClusterPods command to list the pods of a namespace in every cluster
+// concurrently. Pods are ordered by cluster, in the order the contexts were
+// given. Their errors are counted afterwards, like those of a single
+// cluster.
+func LoadClusterPods(clusters []cluster, namespace string, opts PodListOptions) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		lists := make([][]PodInfo, len(clusters))
+		errs := make([]error, len(clusters))
+		var wg sync.WaitGroup
+		for i, c := range clusters {
+			wg.Add(1)
+			go func(i int, c cluster) {
+				defer wg.Done()
+				lists[i], errs[i] = listPodInfos(ctx, c.client, namespace, opts)
+			}(i, c)
+		}
+		wg.Wait()
+
+		msg := LoadPodsMsg{clusterErrs: make(map[string]error)}
+		for i, c := range clusters {
+			if errs[i] != nil {
+				msg.clusterErrs[c.context] = errs[i]
+				continue
+			}
+			for _, pod := range lists[i] {
+				pod.Context = c.context
+				msg.pods = append(msg.pods, pod)
+			}
+		}
+		if len(msg.clusterErrs) == len(clusters) {
+			msg.err = errors.Join(errs...)
+		}
+
+		return msg
+	}
+}
+
+// clusterSummary holds the totals of one cluster shown above the pod grid
+type clusterSummary struct {
+	context string
+	podTotals
+	err error
+}
+
+// clusterSummaries totals the listed pods of every cluster
+func (m Model) clusterSummaries() []clusterSummary {
+	summaries := make([]clusterSummary, len(m.clusters))
+	index := make(map[string]int, len(m.clusters))
+	for i, c := range m.clusters {
+		summaries[i] = clusterSummary{context: c.context, err: m.clusterErrs[c.context]}
+		index[c.context] = i
+	}
+
+	for _, pod := range m.allPods {
+		i, ok := index[pod.Context]
+		if !ok {
+			continue
+		}
+		summaries[i].add(pod, m.podErrorCount(pod))
+	}
+	return summaries
+}
+
+// renderClusterSummaries renders the totals of every cluster, or why its
+// pods could not be loaded
+func (m Model) renderClusterSummaries() string {
+	var b strings.Builder
+	b.WriteString(m.localization.Clusters + ":\n")
+	for _, summary := range m.clusterSummaries() {
+		name := m.contextStyle(summary.context).Render(fmt.Sprintf("⎈ %-20s", summary.context))
+		if summary.err != nil {
+			b.WriteString(fmt.Sprintf("  %s %s\n", name, ErrorStyle.Render(summary.err.Error())))
+			continue
+		}
+
+		errorStyle := SuccessStyle
+		if summary.errors > 0 {
+			errorStyle = ErrorStyle
+		}
+		b.WriteString(fmt.Sprintf("  %s %s: %-4d %s: %d/%-4d %s: %-4d %s: %s\n",
+			name,
+			m.localization.Pods, summary.pods,
+			m.localization.Ready, summary.ready, summary.pods,
+			m.localization.Restart, summary.restarts,
+			m.localization.Errors, errorStyle.Render(strconv.Itoa(summary.errors)),
+		))
+	}
+	return b.String()
+}
+
+// clientFor returns the client of a pod's cluster
+func (m Model) clientFor(context string) ClusterClient {
+	for _, c := range m.clusters {
+		if c.context == context {
+			return c.client
+		}
+	}
+	return m.client
+}
+
+// loadNamespaces returns the command listing the namespaces of the active
+// cluster, or of every cluster in fan-out mode
+func (m Model) loadNamespaces() tea.Cmd {
+	if len(m.clusters) > 0 {
+		return LoadClusterNamespaces(m.clusters)
+	}
+	return LoadNamespaces(m.client)
+}
+
+// loadPods returns the command listing the pods of the namespace in the
+// active cluster, or in every cluster in fan-out mode
+func (m Model) loadPods() tea.Cmd {
+	if len(m.clusters) > 0 {
+		return LoadClusterPods(m.clusters, m.namespace, m.podOptions)
+	}
+	return LoadPods(m.client, m.namespace, m.podOptions)
+}
+
+// scanPods returns the command counting the errors of the listed pods not
+// scanned yet, one scan per cluster with its client, or nil when there are
+// none
+func (m *Model) scanPods() tea.Cmd {
+	byContext := make(map[string][]PodInfo)
+	var contexts []string
+	for _, pod := range m.unscannedPods() {
+		if _, ok := byContext[pod.Context]; !ok {
+			contexts = append(contexts, pod.Context)
+		}
+		byContext[pod.Context] = append(byContext[pod.Context], pod)
+	}
+
+	cmds := make([]tea.Cmd, 0, len(contexts))
+	for _, name := range contexts {
+		cmds = append(cmds, ScanPods(m.clientFor(name), m.namespace, byContext[name], m.window, m.rules.ForNamespace(m.namespace)))
+	}
+	m.scans = len(cmds)
+	return tea.Batch(cmds...)
+}
+
+// loadLogs returns the command analyzing the current target with the
+// client of its cluster
+func (m Model) loadLogs() tea.Cmd {
+	target := m.currentTarget()
+	return LoadLogs(m.clientFor(target.context), m.namespace, target, m.eventObjects(target), m.window, m.rules.ForNamespace(m.namespace))
+}
+
+// activeContexts returns the contexts whose data is on screen
+func (m Model) activeContexts() []string {
+	if len(m.clusters) > 0 {
+		contexts := make([]string, len(m.clusters))
+		for i, c := range m.clusters {
+			contexts[i] = c.context
+		}
+		return contexts
+	}
+	if m.kubeContext != "" {
+		return []string{m.kubeContext}
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Sort modes of the pod grid
+const (
+	SortUnhealthy = iota
+	SortName
+	SortStatus
+	SortRestarts
+	SortAge
+	SortErrors
+	sortCount
+)
+
+// sortNames are the --sort values of the sort modes
+var sortNames = []string{"unhealthy", "name", "status", "restarts", "age", "errors"}
+
+// parseSortMode returns the sort mode of a --sort value
+func parseSortMode(name string) (int, bool) {
+	for mode, sortName := range sortNames {
+		if sortName == name {
+			return mode, true
+		}
+	}
+	return SortUnhealthy, false
+}
+
+// sortLabel returns the localized name of a sort mode
+func (m Model) sortLabel(mode int) string {
+	switch mode {
+	case SortName:
+		return m.localization.Name
+	case SortStatus:
+		return m.localization.Status
+	case SortRestarts:
+		return m.localization.Restart
+	case SortAge:
+		return m.localization.Age
+	case SortErrors:
+		return m.localization.Errors
+	}
+	return m.localization.UnhealthyFirst
+}
+
+// healthyStates are the container states that do not make a pod unhealthy
+var healthyStates = map[string]bool{
+	"Running":           true,
+	"Completed":         true,
+	"Waiting":           true,
+	"ContainerCreating": true,
+	"PodInitializing":   true,
+}
+
+// podSeverity ranks how unhealthy a pod is: 4 failing, 3 not ready, 2
+// pending, 1 terminating and 0 healthy
+func podSeverity(pod PodInfo) int {
+	if isFailedStatus(pod.Status) {
+		return 4
+	}
+	for _, container := range pod.Containers {
+		if !healthyStates[container.State] {
+			return 4
+		}
+	}
+	switch {
+	case pod.Status == "Succeeded" || pod.Status == "Completed":
+		return 0
+	case isPendingStatus(pod.Status):
+		return 2
+	case pod.Status == "Terminating":
+		return 1
+	}
+	if pod.Ready != "True" {
+		return 3
+	}
+	return 0
+}
+
+// comparePods orders two pods by the sort mode, worst or newest first,
+// falling back to their names
+func (m Model) comparePods(a, b PodInfo) int {
+	restarts := func(pod PodInfo) int {
+		restarts, _ := strconv.Atoi(pod.Restarts)
+		return restarts
+	}
+
+	var keys []int // Pairs of sort keys of a and b, greater first
+	switch m.sortMode {
+	case SortUnhealthy:
+		keys = []int{
+			podSeverity(a), podSeverity(b),
+			restarts(a), restarts(b),
+			m.podErrorCount(a), m.podErrorCount(b),
+		}
+	case SortStatus:
+		keys = []int{podSeverity(a), podSeverity(b)}
+	case SortRestarts:
+		keys = []int{restarts(a), restarts(b)}
+	case SortErrors:
+		// The count of the last analysis when the pod was opened
+		keys = []int{m.podErrorCount(a), m.podErrorCount(b)}
+	case SortAge:
+		if !a.Created.Equal(b.Created) {
+			return b.Created.Compare(a.Created)
+		}
+	}
+	for i := 0; i < len(keys); i += 2 {
+		if keys[i] != keys[i+1] {
+			return keys[i+1] - keys[i]
+		}
+	}
+	return strings.Compare(a.Name, b.Name)
+}
+
+// cycleSort switches the pod grid to the next sort mode
+func (m *Model) cycleSort() {
+	m.sortMode = (m.sortMode + 1) % sortCount
+	m.applyPodFilter()
+}
+
+// fuzzyMatch reports whether the characters of query appear in name in
+// order, ignoring case. Higher scores mean closer matches: consecutive
+// characters and characters starting a word count more.
+func fuzzyMatch(query, name string) (int, bool) {
+	pattern := []rune(strings.ToLower(query))
+	if len(pattern) == 0 {
+		return 0, true
+	}
+
+	score, p := 0, 0
+	previous := -2
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.ToLower(r) != pattern[p] {
+			continue
+		}
+		score++
+		if i == previous+1 {
+			score += 2
+		}
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += 3
+		}
+		previous = i
+		if p++; p == len(pattern) {
+			return score, true
+		}
+	}
+	return 0, false
+}
+
+// fuzzyFilter returns the names matching query, best matches first and
+// otherwise in their original order
+func fuzzyFilter(query string, names []string) []int {
+	type match struct {
+		index int
+		score int
+	}
+	var matches []match
+	for i, name := range names {
+		if score, ok := fuzzyMatch(query, name); ok {
+			matches = append(matches, match{i, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	indexes := make([]int, len(matches))
+	for i, match := range matches {
+		indexes[i] = match.index
+	}
+	return indexes
+}
+
+// applyPodFilter lists the loaded pods matching the name filter in the
+// order of the sort mode, keeping the same pod selected
+func (m *Model) applyPodFilter() {
+	selected, ok := m.selected()
+	m.pods = m.pods[:0:0]
+	for _, pod := range m.allPods {
+		if _, match := fuzzyMatch(m.podQuery, pod.Name); match {
+			m.pods = append(m.pods, pod)
+		}
+	}
+	m.sortPods()
+	m.selectedPod = min(m.selectedPod, max(0, len(m.pods)-1))
+	if ok {
+		m.selectPod(selected)
+	}
+}
+
+// setNamespaces replaces the loaded namespaces
+func (m *Model) setNamespaces(namespaces []string) {
+	m.loadedNS = namespaces
+	m.applyNamespaceFilter()
+}
+
+// applyNamespaceFilter lists the loaded namespaces matching the name
+// filter, best matches first, keeping the same namespace selected
+func (m *Model) applyNamespaceFilter() {
+	selected := ""
+	if m.selectedNS < len(m.namespaces) {
+		selected = m.namespaces[m.selectedNS]
+	}
+
+	m.namespaces = m.namespaces[:0:0]
+	for _, i := range fuzzyFilter(m.nsQuery, m.loadedNS) {
+		m.namespaces = append(m.namespaces, m.loadedNS[i])
+	}
+
+	m.selectedNS = min(m.selectedNS, max(0, len(m.namespaces)-1))
+	for i, name := range m.namespaces {
+		if name == selected {
+			m.selectedNS = i
+		}
+	}
+	// Keep the selection on screen
+	m.pageOffset = min(m.pageOffset, m.selectedNS)
+	if maxVisible := m.getMaxVisibleItems(); m.selectedNS >= m.pageOffset+maxVisible {
+		m.pageOffset = m.selectedNS - maxVisible + 1
+	}
+}
+
+// query returns the name filter of the current view
+func (m *Model) query() *string {
+	if m.currentView == "namespaces" {
+		return &m.nsQuery
+	}
+	return &m.podQuery
+}
+
+// applyQuery refilters the current view after its name filter changed
+func (m *Model) applyQuery() {
+	if m.currentView == "namespaces" {
+		m.applyNamespaceFilter()
+	} else {
+		m.onGroup = false
+		m.applyPodFilter()
+	}
+}
+
+// clearQuery removes the name filter of the current view
+func (m *Model) clearQuery() {
+	*m.query() = ""
+	m.applyQuery()
+}
+
+// handleQueryKey edits the name filter prompt of the pods and namespaces
+// views; the list is filtered as the query is typed
+func (m Model) handleQueryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	query := m.query()
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.stopFollow()
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.querying = false
+		m.clearQuery()
+		return m, nil
+	case tea.KeyEnter:
+		m.querying = false
+		return m, nil
+	case tea.KeyBackspace:
+		if runes := []rune(*query); len(runes) > 0 {
+			*query = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		*query += " "
+	case tea.KeyRunes:
+		*query += string(msg.Runes)
+	default:
+		return m, nil
+	}
+
+	m.applyQuery()
+	return m, nil
+}
+
+// queryStatus renders the name filter prompt, or the active filter with how
+// many items it matches, or "" when the view is not filtered
+func (m Model) queryStatus(query string, shown, total int) string {
+	if !m.querying && query == "" {
+		return ""
+	}
+	status := m.localization.Filter + ": "
+	if m.querying {
+		status += SelectedStyle.Render(query + "█")
+	} else {
+		status += SelectedStyle.Render(query)
+	}
+	status += NormalStyle.Render(" (" + strconv.Itoa(shown) + "/" + strconv.Itoa(total) + ")")
+	if m.querying {
+		status += " " + NormalStyle.Render(m.localization.FilterHelp)
+	}
+	return status + "\n\n"
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query, name string
+		wantScore   int
+		wantMatch   bool
+	}{
+		{"", "anything", 0, true},
+		{"api", "api-server", 10, true}, // Word start and consecutive
+		{"api", "my-api", 10, true},
+		{"api", "rapid", 7, true}, // Consecutive inside a word
+		{"API", "api-server", 10, true},
+		{"ckt", "checkout-7d9f", 6, true},
+		{"7", "checkout-7d9f", 4, true},
+		{"ia", "api", 0, false}, // Characters must appear in order
+		{"xyz", "api", 0, false},
+	}
+	for _, tt := range tests {
+		score, ok := fuzzyMatch(tt.query, tt.name)
+		if score != tt.wantScore || ok != tt.wantMatch {
+			t.Errorf("fuzzyMatch(%q, %q) = %d, %v, want %d, %v", tt.query, tt.name, score, ok, tt.wantScore, tt.wantMatch)
+		}
+	}
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	names := []string{"rapid", "api-server", "web", "my-api", "payments-api"}
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"", []int{0, 1, 2, 3, 4}},
+		// Ties keep their order; payments-api matches the first a greedily
+		{"api", []int{1, 3, 0, 4}},
+		{"web", []int{2}},
+		{"pay", []int{4}},
+		{"zzz", []int{}},
+	}
+	for _, tt := range tests {
+		if got := fuzzyFilter(tt.query, names); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("fuzzyFilter(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
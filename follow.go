@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxFollowBatch caps how many streamed lines are delivered in one message
+const maxFollowBatch = 500
+
+// logFollower streams the logs of a log target in the background, like
+// `kubectl logs -f`. Lines are handed to the Update loop in batches.
+type logFollower struct {
+	target logTarget
+	lines  chan timedLine
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// newLogFollower prepares a follower for a target; nothing is streamed
+// until the commands returned by start are run
+func newLogFollower(target logTarget) *logFollower {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &logFollower{
+		target: target,
+		lines:  make(chan timedLine, maxFollowBatch),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// start returns the commands that open the stream and wait for its first
+// lines. after is the API timestamp of the last analyzed line; when zero,
+// nothing was analyzed yet and the stream starts at the time range.
+func (f *logFollower) start(client ClusterClient, namespace string, window timeRange, after time.Time) tea.Cmd {
+	opts := window.logOptions()
+	if !after.IsZero() {
+		opts = LogOptions{SinceTime: after}
+	}
+	opts.Timestamps = true
+	opts.Follow = true
+	return tea.Batch(f.stream(client, namespace, opts, after), f.wait())
+}
+
+// stop cancels the stream; pending messages from it are ignored
+func (f *logFollower) stop() {
+	f.cancel()
+}
+
+// stream reads the follow streams of every container of the target until
+// they end or are cancelled. Lines are tagged with their source when more
+// than one container is followed.
+func (f *logFollower) stream(client ClusterClient, namespace string, opts LogOptions, after time.Time) tea.Cmd {
+	return func() tea.Msg {
+		defer close(f.lines)
+
+		streams := f.target.streams()
+		tagged := len(streams) > 1
+		errs := make([]error, len(streams))
+		var wg sync.WaitGroup
+		for i, stream := range streams {
+			wg.Add(1)
+			go func(i int, stream logStream) {
+				defer wg.Done()
+				prefix := ""
+				if tagged {
+					prefix = "[" + stream.tag + "] "
+				}
+				errs[i] = f.streamContainer(client, namespace, stream, prefix, opts, after)
+			}(i, stream)
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			if err != nil {
+				return FollowStoppedMsg{follower: f, err: err}
+			}
+		}
+		return FollowStoppedMsg{follower: f}
+	}
+}
+
+// streamContainer forwards the followed lines of one container logged after
+// the last analyzed line. The API truncates sinceTime to whole seconds, so
+// the stream starts with lines that were already analyzed.
+func (f *logFollower) streamContainer(client ClusterClient, namespace string, source logStream, prefix string, opts LogOptions, after time.Time) error {
+	opts.Container = source.container
+	stream, err := client.StreamLogs(f.ctx, namespace, source.pod, opts)
+	if err != nil {
+		return ignoreCanceled(err)
+	}
+	defer stream.Close()
+
+	var last time.Time
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		// Lines without a timestamp continue the previous one
+		at, text, ok := splitTimestamp(scanner.Text())
+		if ok {
+			last = at
+		} else {
+			at = last
+		}
+		if !after.IsZero() && !at.After(after) {
+			continue
+		}
+		select {
+		case f.lines <- timedLine{at: at, tag: source.tag, text: prefix + text}:
+		case <-f.ctx.Done():
+			return nil
+		}
+	}
+
+	return ignoreCanceled(scanner.Err())
+}
+
+// wait blocks for the next streamed line and drains whatever else is ready
+func (f *logFollower) wait() tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-f.lines
+		if !ok {
+			return nil
+		}
+
+		lines := []timedLine{line}
+		for len(lines) < maxFollowBatch {
+			select {
+			case line, ok := <-f.lines:
+				if !ok {
+					return LogLinesMsg{follower: f, lines: lines}
+				}
+				lines = append(lines, line)
+			default:
+				return LogLinesMsg{follower: f, lines: lines}
+			}
+		}
+
+		return LogLinesMsg{follower: f, lines: lines}
+	}
+}
+
+// ignoreCanceled hides the error produced by stopping a follow on purpose
+func ignoreCanceled(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -60,190 +62,608 @@ func (m Model) getPodsPerRow() int {
 	return podsPerRow
 }
 
+// gridRow is a row of the pod grid: a group header or a row of pod boxes
+type gridRow struct {
+	group  string
+	header bool
+	pods   []int // Pod indexes of the row, or of the whole group for a header
+}
+
+// podRows lays out the pod grid. Every group starts with its header row
+// followed by its pods, unless the group is collapsed.
+func (m Model) podRows() []gridRow {
+	podsPerRow := m.getPodsPerRow()
+	var rows []gridRow
+	for start := 0; start < len(m.pods); {
+		group := m.podGroup(m.pods[start])
+		end := start + 1
+		for end < len(m.pods) && m.podGroup(m.pods[end]) == group {
+			end++
+		}
+
+		if group != "" {
+			header := gridRow{group: group, header: true}
+			for i := start; i < end; i++ {
+				header.pods = append(header.pods, i)
+			}
+			rows = append(rows, header)
+		}
+		if !m.collapsed[group] || m.flatPods {
+			for i := start; i < end; i += podsPerRow {
+				row := gridRow{group: group}
+				for j := i; j < min(end, i+podsPerRow); j++ {
+					row.pods = append(row.pods, j)
+				}
+				rows = append(rows, row)
+			}
+		}
+		start = end
+	}
+	return rows
+}
+
+// gridCursor returns the row and column of the selection in the grid
+func (m Model) gridCursor(rows []gridRow) (int, int) {
+	group := ""
+	if m.selectedPod < len(m.pods) {
+		group = m.podGroup(m.pods[m.selectedPod])
+	}
+	for i, row := range rows {
+		if row.header && row.group == group && !m.flatPods && (m.onGroup || m.collapsed[group]) {
+			return i, 0
+		}
+		if !row.header && len(row.pods) > 0 && m.selectedPod >= row.pods[0] && m.selectedPod <= row.pods[len(row.pods)-1] {
+			return i, m.selectedPod - row.pods[0]
+		}
+	}
+	return 0, 0
+}
+
+// moveGridRows moves the selection by delta rows, staying in the same
+// column where the row is long enough. Group headers can be selected when
+// pods are grouped by workload.
+func (m *Model) moveGridRows(delta int) {
+	rows := m.podRows()
+	if len(rows) == 0 {
+		return
+	}
+	row, col := m.gridCursor(rows)
+	target := max(0, min(len(rows)-1, row+delta))
+	// Cluster labels of the flat grid are not selectable
+	for rows[target].header && m.flatPods {
+		if target+1 < len(rows) && (delta > 0 || target == 0) {
+			target++
+		} else {
+			target--
+		}
+	}
+	if row+delta < 0 {
+		col = 0
+	} else if row+delta >= len(rows) && !rows[target].header {
+		col = len(rows[target].pods) - 1
+	}
+
+	m.onGroup = rows[target].header
+	if m.onGroup {
+		m.selectedPod = rows[target].pods[0]
+	} else {
+		m.selectedPod = rows[target].pods[min(col, len(rows[target].pods)-1)]
+	}
+}
+
 // handleKeyMsg processes keyboard input
 func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.searching {
+		return m.handleSearchKey(msg)
+	}
+	if m.jumping {
+		return m.handleJumpKey(msg)
+	}
+	if m.filtering {
+		return m.handleSelectorKey(msg)
+	}
+	if m.querying {
+		return m.handleQueryKey(msg)
+	}
+	if m.picking {
+		return m.handleTimeKey(msg)
+	}
+
 	switch msg.Type {
 	case tea.KeyCtrlC:
 		return m, tea.Quit
 	case tea.KeyEsc:
-		if m.currentView == "analysis" {
+		if m.currentView == "analysis" && m.contextLine > 0 {
+			// Back from the context view to the tab
+			m.contextLine = 0
+		} else if m.currentView == "analysis" && m.searchQuery != "" {
+			// The first Esc only clears the search
+			m.clearSearch()
+		} else if m.currentView == "analysis" {
+			m.stopFollow()
+			m.currentView = m.analysisParentView()
+		} else if m.currentView == "containers" || m.currentView == "diff" {
 			m.currentView = "pods"
+		} else if m.currentView == "pods" && m.diffBase != nil {
+			// The first Esc only clears the pod marked for a diff
+			m.diffBase = nil
+		} else if (m.currentView == "pods" || m.currentView == "namespaces") && *m.query() != "" {
+			// The first Esc only clears the name filter
+			m.clearQuery()
 		} else if m.currentView == "pods" && m.namespace != "" {
 			m.currentView = "namespaces"
 			m.namespace = ""
+		} else if m.currentView == "namespaces" {
+			return m, m.openContextPicker()
+		} else if m.currentView == "contexts" {
+			return m, m.closeContextPicker()
 		}
 	}
 
 	switch msg.String() {
 	case "q":
+		m.stopFollow()
 		return m, tea.Quit
 	case "up", "k":
-		if m.currentView == "namespaces" && m.selectedNS > 0 {
+		if m.currentView == "contexts" && m.selectedKube > 0 {
+			m.selectedKube--
+		} else if m.currentView == "namespaces" && m.selectedNS > 0 {
 			m.selectedNS--
 			// Check if we need to scroll up
 			if m.selectedNS < m.pageOffset {
 				m.pageOffset = m.selectedNS
 			}
+		} else if m.currentView == "containers" && m.selectedCtr > 0 {
+			m.selectedCtr--
 		} else if m.currentView == "analysis" {
-			// Scroll up in log analysis
-			if m.logOffset < 100 { // Limit scroll to prevent going too far
-				m.logOffset += 5
-			}
+			m.scrollLog(-1)
 		} else if m.currentView == "pods" && len(m.pods) > 0 {
-			podsPerRow := m.getPodsPerRow()
-			// Move up one row
-			newIndex := m.selectedPod - podsPerRow
-			if newIndex >= 0 {
-				m.selectedPod = newIndex
-			} else {
-				// If we can't move up a full row, move to the first pod in current row
-				currentRow := m.selectedPod / podsPerRow
-				if currentRow > 0 {
-					m.selectedPod = 0
-				}
-			}
+			m.moveGridRows(-1)
 		}
 	case "down", "j":
-		if m.currentView == "namespaces" && m.selectedNS < len(m.namespaces)-1 {
+		if m.currentView == "contexts" && m.selectedKube < len(m.kubeContexts)-1 {
+			m.selectedKube++
+		} else if m.currentView == "namespaces" && m.selectedNS < len(m.namespaces)-1 {
 			m.selectedNS++
 			// Check if we need to scroll down
 			maxVisible := m.getMaxVisibleItems()
 			if m.selectedNS >= m.pageOffset+maxVisible {
 				m.pageOffset = m.selectedNS - maxVisible + 1
 			}
+		} else if m.currentView == "containers" && m.selectedCtr < len(m.pods[m.selectedPod].Containers) {
+			m.selectedCtr++
 		} else if m.currentView == "analysis" {
-			// Scroll down in log analysis
-			if m.logOffset > 0 {
-				m.logOffset -= 5
-				if m.logOffset < 0 {
-					m.logOffset = 0
-				}
-			}
+			m.scrollLog(1)
 		} else if m.currentView == "pods" && len(m.pods) > 0 {
-			podsPerRow := m.getPodsPerRow()
-			// Move down one row
-			newIndex := m.selectedPod + podsPerRow
-			if newIndex < len(m.pods) {
-				m.selectedPod = newIndex
-			} else {
-				// If we can't move down a full row, move to the last pod
-				m.selectedPod = len(m.pods) - 1
-			}
+			m.moveGridRows(1)
 		}
 	case "left", "h":
-		if m.currentView == "pods" && m.selectedPod > 0 {
-			podsPerRow := m.getPodsPerRow()
+		if m.currentView == "analysis" && len(m.pods) > 0 {
+			m.moveTimeCursor(-1)
+		} else if m.currentView == "pods" && m.selectedPod > 0 {
 			// Check if we're not at the beginning of a row
-			currentCol := m.selectedPod % podsPerRow
-
-			if currentCol > 0 {
+			rows := m.podRows()
+			if row, col := m.gridCursor(rows); !rows[row].header && col > 0 {
 				m.selectedPod--
 			}
 		}
 	case "right", "l":
-		if m.currentView == "pods" && m.selectedPod < len(m.pods)-1 {
-			podsPerRow := m.getPodsPerRow()
+		if m.currentView == "analysis" && len(m.pods) > 0 {
+			m.moveTimeCursor(1)
+		} else if m.currentView == "pods" && m.selectedPod < len(m.pods)-1 {
 			// Check if we're not at the end of a row
-			currentRow := m.selectedPod / podsPerRow
-			currentCol := m.selectedPod % podsPerRow
-			maxCol := min(podsPerRow-1, len(m.pods)-currentRow*podsPerRow-1)
-
-			if currentCol < maxCol {
+			rows := m.podRows()
+			if row, col := m.gridCursor(rows); !rows[row].header && col < len(rows[row].pods)-1 {
 				m.selectedPod++
 			}
 		}
 	case "pageup", "ctrl+u":
-		if m.currentView == "pods" && len(m.pods) > 0 {
-			podsPerRow := m.getPodsPerRow()
-			// Move up by multiple rows (like page up)
-			rowsToMove := 3
-			newIndex := m.selectedPod - (podsPerRow * rowsToMove)
-			if newIndex < 0 {
-				m.selectedPod = 0
-			} else {
-				m.selectedPod = newIndex
+		if m.currentView == "analysis" {
+			// Full page, or half a page with ctrl+u
+			page := m.logPaneHeight()
+			if msg.String() == "ctrl+u" {
+				page /= 2
 			}
+			m.scrollLog(-page)
+		} else if m.currentView == "pods" && len(m.pods) > 0 {
+			// Move up by multiple rows (like page up)
+			m.moveGridRows(-3)
 		}
 	case "pagedown", "ctrl+d":
-		if m.currentView == "pods" && len(m.pods) > 0 {
-			podsPerRow := m.getPodsPerRow()
-			// Move down by multiple rows (like page down)
-			rowsToMove := 3
-			newIndex := m.selectedPod + (podsPerRow * rowsToMove)
-			if newIndex >= len(m.pods) {
-				m.selectedPod = len(m.pods) - 1
-			} else {
-				m.selectedPod = newIndex
+		if m.currentView == "analysis" {
+			page := m.logPaneHeight()
+			if msg.String() == "ctrl+d" {
+				page /= 2
 			}
+			m.scrollLog(page)
+		} else if m.currentView == "pods" && len(m.pods) > 0 {
+			// Move down by multiple rows (like page down)
+			m.moveGridRows(3)
 		}
 	case "home", "g":
-		if m.currentView == "pods" && len(m.pods) > 0 {
-			m.selectedPod = 0
+		if m.currentView == "analysis" {
+			m.scrollLog(-math.MaxInt32)
+		} else if m.currentView == "pods" && len(m.pods) > 0 {
+			m.moveGridRows(-math.MaxInt32)
 		}
 	case "end", "G":
-		if m.currentView == "pods" && len(m.pods) > 0 {
-			m.selectedPod = len(m.pods) - 1
+		if m.currentView == "analysis" {
+			m.scrollLog(math.MaxInt32)
+		} else if m.currentView == "pods" && len(m.pods) > 0 {
+			m.moveGridRows(math.MaxInt32)
+		}
+	case ":":
+		// Go to a line number
+		if m.currentView == "analysis" && len(m.pods) > 0 {
+			m.jumping = true
+			m.jumpInput = ""
 		}
 	case "enter":
-		if m.currentView == "namespaces" && len(m.namespaces) > 0 {
+		if m.currentView == "contexts" && len(m.kubeContexts) > 0 {
+			opts := m.kubeOptions
+			opts.Context = m.kubeContexts[m.selectedKube]
+			m.loading = true
+			return m, SwitchContext(opts)
+		} else if m.currentView == "namespaces" && len(m.namespaces) > 0 {
 			m.namespace = m.namespaces[m.selectedNS]
+			m.podQuery = ""
 			m.currentView = "pods"
 			m.loading = true
-			return m, LoadPods(m.namespace)
+			return m, m.loadPods()
 		} else if m.currentView == "pods" && len(m.pods) > 0 {
-			selectedPod := m.pods[m.selectedPod].Name
+			pod := m.pods[m.selectedPod]
+			m.workload = ""
+			if m.onGroup {
+				// Standalone pods have no workload to analyze as a whole
+				if pod.Workload == "" || m.flatPods {
+					m.toggleGroup()
+					return m, nil
+				}
+				return m, m.analyzeWorkload(m.podGroup(pod))
+			}
+			if len(pod.Containers) > 1 {
+				m.currentView = "containers"
+				m.selectedCtr = 0
+				return m, nil
+			}
+			m.container = ""
+			if len(pod.Containers) == 1 {
+				m.container = pod.Containers[0].Name
+			}
 			m.logOffset = 0 // Reset scroll position when entering analysis
-			return m, LoadLogs(m.namespace, selectedPod, m.since)
+			m.resetTraces()
+			m.clearSearch()
+			m.setTab(TabAll)
+			return m, m.loadLogs()
+		} else if m.currentView == "containers" {
+			m.container = AllContainers
+			if m.selectedCtr > 0 {
+				m.container = m.pods[m.selectedPod].Containers[m.selectedCtr-1].Name
+			}
+			m.logOffset = 0
+			m.resetTraces()
+			m.clearSearch()
+			m.setTab(TabAll)
+			return m, m.loadLogs()
+		} else if m.currentView == "analysis" && m.logTab != TabAll && m.contextLine == 0 {
+			m.openContext()
 		}
 	case "backspace":
 		if m.currentView == "analysis" {
+			m.stopFollow()
+			m.currentView = m.analysisParentView()
+		} else if m.currentView == "containers" || m.currentView == "diff" {
 			m.currentView = "pods"
 		} else if m.currentView == "pods" && m.namespace != "" {
 			m.currentView = "namespaces"
 			m.namespace = ""
+			m.diffBase = nil
+		} else if m.currentView == "namespaces" {
+			return m, m.openContextPicker()
+		} else if m.currentView == "contexts" {
+			return m, m.closeContextPicker()
 		}
 	case "r":
 		// Refresh
 		m.loading = true
-		if m.currentView == "namespaces" {
-			return m, LoadNamespaces()
+		if m.currentView == "contexts" {
+			return m, LoadContexts(m.kubeOptions.Kubeconfig)
+		} else if m.currentView == "namespaces" {
+			return m, m.loadNamespaces()
 		} else if m.currentView == "pods" {
-			return m, LoadPods(m.namespace)
+			// Count the errors of every pod again
+			m.podErrors = nil
+			return m, m.loadPods()
 		} else if m.currentView == "analysis" && len(m.pods) > 0 {
-			selectedPod := m.pods[m.selectedPod].Name
-			return m, LoadLogs(m.namespace, selectedPod, m.since)
+			m.stopFollow()
+			return m, m.loadLogs()
+		} else if m.currentView == "diff" {
+			return m, m.loadDiff(m.diff.base, m.diff.other)
+		}
+		// Nothing to reload in this view, such as the container picker
+		m.loading = false
+	case "f":
+		// Toggle live follow of the analyzed pod, unless the time range
+		// has already ended
+		if m.currentView == "analysis" && len(m.pods) > 0 && (m.follower != nil || !m.window.closed()) {
+			if m.follower != nil {
+				m.stopFollow()
+			} else {
+				m.showPrevious = false
+				target := m.currentTarget()
+				m.follower = newLogFollower(target)
+				return m, m.follower.start(m.clientFor(target.context), m.namespace, m.window, m.logs[target.key()].LastLogged)
+			}
+		}
+	case "p":
+		// Toggle between the current and previous container instance
+		if m.currentView == "analysis" && len(m.pods) > 0 {
+			if _, ok := m.previousLogs[m.currentTarget().key()]; ok && m.follower == nil {
+				m.showPrevious = !m.showPrevious
+				m.logOffset = 0
+				m.resetTraces()
+				m.updateMatches()
+				m.setTab(TabAll)
+			}
+		}
+	case "]", "[":
+		// Jump between stack traces
+		if m.currentView == "analysis" && len(m.pods) > 0 {
+			delta := 1
+			if msg.String() == "[" {
+				delta = -1
+			}
+			m.moveTraceCursor(delta)
+		}
+	case "x":
+		// Expand or collapse the selected stack trace
+		if m.currentView == "analysis" && m.traceCursor > 0 {
+			if m.expanded == nil {
+				m.expanded = make(map[int]bool)
+			}
+			m.expanded[m.traceCursor] = !m.expanded[m.traceCursor]
+		}
+	case "X":
+		// Expand every stack trace, or collapse them when all are expanded
+		if m.currentView == "analysis" && len(m.pods) > 0 {
+			analysis, _ := m.shownAnalysis()
+			expand := false
+			for i := range analysis.Traces {
+				if !m.expanded[i+1] {
+					expand = true
+				}
+			}
+			m.expanded = make(map[int]bool)
+			if expand {
+				for i := range analysis.Traces {
+					m.expanded[i+1] = true
+				}
+			}
+		}
+	case "tab", "shift+tab":
+		// Switch between the All and category tabs
+		if m.currentView == "analysis" && len(m.pods) > 0 {
+			delta := 1
+			if msg.String() == "shift+tab" {
+				delta = -1
+			}
+			m.setTab(m.logTab + delta)
+		}
+	case "1", "2", "3", "4", "5":
+		if m.currentView == "analysis" && len(m.pods) > 0 {
+			m.setTab(int(msg.String()[0] - '1'))
+		}
+	case "+", "-":
+		// Widen or narrow the context view
+		if m.currentView == "analysis" && m.contextLine > 0 {
+			if msg.String() == "+" {
+				m.contextSize = min(50, m.contextLines()+1)
+			} else {
+				m.contextSize = max(1, m.contextLines()-1)
+			}
+		}
+	case "/":
+		// Start a new search in the log pane, or filter the list by name
+		if m.currentView == "analysis" && len(m.pods) > 0 {
+			m.clearSearch()
+			m.searching = true
+		} else if m.currentView == "pods" || m.currentView == "namespaces" {
+			m.querying = true
+		}
+	case "o":
+		// Sort the pods by the next sort mode
+		if m.currentView == "pods" {
+			m.onGroup = false
+			m.cycleSort()
+		}
+	case "n", "N":
+		// Jump between search matches
+		if m.currentView == "analysis" {
+			delta := 1
+			if msg.String() == "N" {
+				delta = -1
+			}
+			m.nextMatch(delta)
+		}
+	case "F":
+		// Show only the lines matching the search
+		if m.currentView == "analysis" && m.search != nil {
+			m.searchFilter = !m.searchFilter
+			if current := m.currentMatch(); current >= 0 {
+				analysis, _ := m.shownAnalysis()
+				m.scrollToEntry(analysis, current)
+			}
+		}
+	case "s":
+		// Filter the pods with a label and field selector
+		if m.currentView == "pods" && m.namespace != "" {
+			m.openSelectorPrompt()
+		}
+	case "A":
+		// Analyze every listed pod of the selected pod's cluster together
+		if m.currentView == "pods" && len(m.pods) > 0 {
+			return m, m.analyzeWorkload(listedPods)
+		}
+	case "z":
+		// Collapse or expand the group of the selected pod
+		if m.currentView == "pods" && len(m.pods) > 0 {
+			m.toggleGroup()
+		}
+	case "Z":
+		// Collapse every group, or expand them all when they already are
+		if m.currentView == "pods" && len(m.pods) > 0 {
+			m.toggleAllGroups()
+		}
+	case "w":
+		// Switch between pods grouped by workload and a flat grid
+		if m.currentView == "pods" && len(m.pods) > 0 {
+			m.toggleFlatPods()
+		}
+	case "d":
+		// Mark the selected pod for a diff, or diff it with the marked one
+		if m.currentView == "pods" && len(m.pods) > 0 {
+			return m, m.markDiff()
+		}
+	case "T":
+		// Pick the time range of the analyzed logs
+		if m.currentView == "pods" || m.currentView == "analysis" || m.currentView == "diff" {
+			m.openTimePicker(m.window, false)
+		}
+	case "b":
+		// Pick the time range of the diff's base side
+		if m.currentView == "diff" {
+			m.openTimePicker(m.diff.before.window, true)
 		}
 	case "t":
 		// Toggle auto-refresh
 		m.autoRefresh = !m.autoRefresh
-		if m.autoRefresh {
-			return m, Tick()
-		}
+		m.refreshFails = 0
 	}
 
 	return m, nil
 }
 
-// CalculateAge calculates pod age from timestamp
-func CalculateAge(timestamp string) string {
-	if timestamp == "" || timestamp == "<none>" {
-		return "Unknown"
+// currentTarget returns the log target of the analyzed workload, or of the
+// selected pod and container
+func (m Model) currentTarget() logTarget {
+	if m.workload != "" {
+		return m.workloadTarget()
+	}
+	return logTargetFor(m.pods[m.selectedPod], m.container)
+}
+
+// analysisParentView returns the view Esc leads back to from the analysis
+func (m Model) analysisParentView() string {
+	if m.workload == "" && len(m.pods) > 0 && len(m.pods[m.selectedPod].Containers) > 1 {
+		return "containers"
+	}
+	return "pods"
+}
+
+// stopFollow stops the live log stream, if any
+func (m *Model) stopFollow() {
+	if m.follower != nil {
+		m.follower.stop()
+		m.follower = nil
+	}
+}
+
+// shownAnalysis returns the analysis displayed in the analysis view: the
+// current or the previous instance of the selected target
+func (m Model) shownAnalysis() (LogAnalysis, bool) {
+	key := m.currentTarget().key()
+	if m.showPrevious {
+		if previous, ok := m.previousLogs[key]; ok {
+			return previous, true
+		}
 	}
+	analysis, ok := m.logs[key]
+	return analysis, ok
+}
 
-	// Parse Kubernetes timestamp format
-	// Example: 2025-07-27T14:27:59Z
-	layout := "2006-01-02T15:04:05Z"
-	t, err := time.Parse(layout, timestamp)
-	if err != nil {
-		// Try alternative format without Z
-		layout = "2006-01-02T15:04:05"
-		t, err = time.Parse(layout, timestamp)
-		if err != nil {
-			return "Unknown"
+// logPaneHeight returns how many log lines fit in the analysis view
+func (m Model) logPaneHeight() int {
+	return max(10, m.height-25) // Reserve space for other UI elements
+}
+
+// visibleLogEntries returns the indexes of the entries shown in the log
+// pane; the frames of collapsed stack traces are hidden behind their header
+// and, in filter mode, only matching lines are shown
+func (m Model) visibleLogEntries(analysis LogAnalysis) []int {
+	if m.searchFilter && m.search != nil {
+		return m.matches
+	}
+	visible := make([]int, 0, len(analysis.Entries))
+	for i, entry := range analysis.Entries {
+		if entry.Trace > 0 && !analysis.isTraceHeader(entry) && !m.expanded[entry.Trace] {
+			continue
 		}
+		visible = append(visible, i)
+	}
+	return visible
+}
+
+// logWindow returns the entries of the log pane and the range of them that
+// is on screen
+func (m Model) logWindow(analysis LogAnalysis) ([]int, int, int) {
+	visible := m.visibleLogEntries(analysis)
+	height := m.logPaneHeight()
+	start := max(0, len(visible)-height-m.logOffset)
+	end := min(len(visible), start+height)
+	return visible, start, end
+}
+
+// scrollToEntry scrolls the All tab so an entry is in the middle,
+// expanding the stack trace it belongs to if needed
+func (m *Model) scrollToEntry(analysis LogAnalysis, index int) {
+	m.logTab = TabAll
+	m.contextLine = 0
+	if entry := analysis.Entries[index]; entry.Trace > 0 && !analysis.isTraceHeader(entry) {
+		if m.expanded == nil {
+			m.expanded = make(map[int]bool)
+		}
+		m.expanded[entry.Trace] = true
+	}
+
+	visible := m.visibleLogEntries(analysis)
+	pos := sort.SearchInts(visible, index)
+	height := m.logPaneHeight()
+	m.logOffset = max(0, len(visible)-height-max(0, pos-height/2))
+}
+
+// resetTraces collapses every stack trace and clears the trace and
+// timeline cursors
+func (m *Model) resetTraces() {
+	m.traceCursor = 0
+	m.timeCursor = 0
+	m.expanded = make(map[int]bool)
+}
+
+// moveTraceCursor selects the next or previous stack trace and scrolls the
+// log pane to its header
+func (m *Model) moveTraceCursor(delta int) {
+	analysis, ok := m.shownAnalysis()
+	if !ok || len(analysis.Traces) == 0 {
+		return
+	}
+
+	cursor := m.traceCursor + delta
+	if cursor < 1 {
+		cursor = len(analysis.Traces)
+	} else if cursor > len(analysis.Traces) {
+		cursor = 1
+	}
+	m.traceCursor = cursor
+	m.scrollToEntry(analysis, analysis.Traces[cursor-1].Line-1)
+}
+
+// CalculateAge calculates pod age from its creation time
+func CalculateAge(created time.Time) string {
+	if created.IsZero() {
+		return "Unknown"
 	}
 
-	duration := time.Since(t)
+	duration := time.Since(created)
 
 	if duration.Hours() < 1 {
 		return fmt.Sprintf("%.0fm", duration.Minutes())
@@ -267,28 +687,67 @@ func GetAutoRefreshIndicator(autoRefresh, blinkState bool) string {
 	return "⏸️"
 }
 
+// pendingStatuses are the statuses of pods that are still starting
+var pendingStatuses = map[string]bool{
+	"Pending":           true,
+	"ContainerCreating": true,
+	"PodInitializing":   true,
+	"SchedulingGated":   true,
+}
+
+// isPendingStatus reports whether a pod is still starting, including while
+// its init containers run ("Init:1/3")
+func isPendingStatus(status string) bool {
+	var done, total int
+	if _, err := fmt.Sscanf(status, "Init:%d/%d", &done, &total); err == nil {
+		return true
+	}
+	return pendingStatuses[status]
+}
+
+// isFailedStatus reports whether a status means the pod or one of its
+// containers failed, such as CrashLoopBackOff, OOMKilled, Evicted or
+// Init:Error
+func isFailedStatus(status string) bool {
+	switch status {
+	case "Running", "NotReady", "Succeeded", "Completed", "Terminating", "Unknown":
+		return false
+	}
+	return !isPendingStatus(status)
+}
+
 // GetStatusIcon returns appropriate icon for pod status
 func GetStatusIcon(status, ready string) string {
-	switch status {
+	switch strings.TrimPrefix(status, "Init:") {
 	case "Running":
 		if ready == "True" {
 			return "✅"
 		}
 		return "🟡"
-	case "Pending":
-		return "⏳"
-	case "Failed", "Error":
-		return "❌"
-	case "Succeeded":
+	case "NotReady":
+		return "🟡"
+	case "Succeeded", "Completed":
 		return "✅"
 	case "Terminating":
 		return "🟠"
 	case "CrashLoopBackOff":
 		return "💥"
-	case "ImagePullBackOff":
+	case "OOMKilled":
+		return "💀"
+	case "Evicted":
+		return "🚫"
+	case "ImagePullBackOff", "ErrImagePull":
 		return "📥"
-	case "ContainerCreating":
+	case "ContainerCreating", "PodInitializing":
 		return "🔧"
+	case "Unknown":
+		return "❔"
+	}
+	switch {
+	case isPendingStatus(status):
+		return "⏳"
+	case isFailedStatus(status):
+		return "❌"
 	default:
 		return "❔"
 	}
@@ -296,20 +755,33 @@ func GetStatusIcon(status, ready string) string {
 
 // GetStatusStyle returns appropriate style for pod status
 func GetStatusStyle(status string) lipgloss.Style {
-	switch status {
-	case "Running":
+	switch {
+	case status == "Running":
 		return RunningStyle
-	case "Pending", "ContainerCreating":
+	case status == "NotReady" || isPendingStatus(status):
 		return PendingStyle
-	case "Failed", "Error", "CrashLoopBackOff", "ImagePullBackOff":
-		return FailedStyle
-	case "Terminating":
+	case status == "Terminating":
 		return TerminatingStyle
+	case isFailedStatus(status):
+		return FailedStyle
 	default:
 		return UnknownStyle
 	}
 }
 
+// categoryStyle returns the style of lines classified into a category
+func categoryStyle(category string) lipgloss.Style {
+	switch category {
+	case CategoryError:
+		return ErrorStyle
+	case CategoryWarning:
+		return WarningStyle
+	case CategoryInfo:
+		return InfoStyle
+	}
+	return NormalStyle
+}
+
 // formatSummaryLine formats a summary line with style
 func formatSummaryLine(label, value string, count int) string {
 	switch label {
@@ -324,8 +796,35 @@ func formatSummaryLine(label, value string, count int) string {
 	}
 }
 
-// renderPodBox creates a styled box for a single pod with dynamic width
-func (m Model) renderPodBox(pod PodInfo, isSelected bool, width int) string {
+// formatSummary lists the detected log formats with their line counts
+func formatSummary(counts map[string]int) string {
+	var parts []string
+	for _, format := range []string{FormatJSON, FormatLogfmt, FormatKlog, FormatPlain} {
+		if counts[format] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", format, counts[format]))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// podBoxLines is the number of content lines of a pod box without a status
+// reason
+const podBoxLines = 5
+
+// rowBoxLines returns the content lines of the pod boxes of a grid row, one
+// more when a pod of the row has a status reason
+func (m Model) rowBoxLines(row gridRow) int {
+	for _, i := range row.pods {
+		if m.pods[i].Reason != "" {
+			return podBoxLines + 1
+		}
+	}
+	return podBoxLines
+}
+
+// renderPodBox creates a styled box for a single pod with dynamic width,
+// padded to the given number of content lines
+func (m Model) renderPodBox(pod PodInfo, isSelected bool, width, lines int) string {
 	// Determine box style based on selection
 	boxStyle := PodBoxStyle.Width(width)
 	nameStyle := NormalStyle
@@ -343,7 +842,7 @@ func (m Model) renderPodBox(pod PodInfo, isSelected bool, width int) string {
 
 	// Format status with appropriate color and blinking
 	statusText := fmt.Sprintf("%s %s", pod.StatusIcon, pod.Status)
-	if pod.Status == "Failed" || pod.Status == "Error" || pod.Status == "CrashLoopBackOff" {
+	if isFailedStatus(pod.Status) {
 		if m.blinkState {
 			statusText = ErrorStyle.Render(statusText)
 		} else {
@@ -374,6 +873,13 @@ func (m Model) renderPodBox(pod PodInfo, isSelected bool, width int) string {
 		}
 	}
 
+	// Error lines counted when the pods were scanned
+	if errors := m.podErrorCount(pod); errors > 0 {
+		restartText += "  " + ErrorStyle.Render(fmt.Sprintf("%d errors", errors))
+	} else if errors == 0 {
+		restartText += "  " + SuccessStyle.Render("0 errors")
+	}
+
 	// Create box content
 	content := fmt.Sprintf("%s\n%s\n%s\n%s\nAge: %s",
 		nameStyle.Render(displayName),
@@ -382,6 +888,10 @@ func (m Model) renderPodBox(pod PodInfo, isSelected bool, width int) string {
 		restartText,
 		InfoStyle.Render(pod.Age),
 	)
+	if pod.Reason != "" {
+		content += "\n" + WarningStyle.Render(m.truncateLogLine(pod.Reason, maxNameLen))
+	}
+	content += strings.Repeat("\n", max(0, lines-strings.Count(content, "\n")-1))
 
 	return boxStyle.Render(content)
 }
@@ -430,6 +940,9 @@ func (m Model) truncateLogLine(line string, maxWidth int) string {
 	if len(line) <= maxWidth {
 		return line
 	}
+	if maxWidth <= 0 {
+		return ""
+	}
 	if maxWidth < 10 {
 		return line[:maxWidth]
 	}
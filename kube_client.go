@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// KubeClient implements ClusterClient on top of client-go
+type KubeClient struct {
+	clientset kubernetes.Interface
+	context   string
+}
+
+// KubeOptions selects the kubeconfig file and context a KubeClient connects
+// with. Empty fields fall back to kubectl's defaults.
+type KubeOptions struct {
+	Kubeconfig string
+	Context    string
+}
+
+// clientConfig returns the client configuration for the options, following
+// the default loading rules (KUBECONFIG, ~/.kube/config or in-cluster
+// configuration) unless a kubeconfig file is given
+func (o KubeOptions) clientConfig() clientcmd.ClientConfig {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: o.Context}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+}
+
+// NewKubeClient builds a client for the kubeconfig context selected by opts
+func NewKubeClient(opts KubeOptions) (*KubeClient, error) {
+	clientConfig := opts.clientConfig()
+
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("creating kubernetes client: %w", err)
+	}
+
+	// The in-cluster configuration has no context name
+	name := opts.Context
+	if raw, err := clientConfig.RawConfig(); err == nil && name == "" {
+		name = raw.CurrentContext
+	}
+
+	return &KubeClient{clientset: clientset, context: name}, nil
+}
+
+// Context returns the name of the kubeconfig context the client uses
+func (c *KubeClient) Context() string {
+	return c.context
+}
+
+// ListNamespaces returns the names of all namespaces, sorted
+func (c *KubeClient) ListNamespaces(ctx context.Context) ([]string, error) {
+	list, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]string, 0, len(list.Items))
+	for _, ns := range list.Items {
+		namespaces = append(namespaces, ns.Name)
+	}
+	sort.Strings(namespaces)
+
+	return namespaces, nil
+}
+
+// ListPods returns the pods of a namespace matching opts, sorted by name
+func (c *KubeClient) ListPods(ctx context.Context, namespace string, opts PodListOptions) ([]corev1.Pod, error) {
+	list, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: opts.LabelSelector,
+		FieldSelector: opts.FieldSelector,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pods := list.Items
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
+	return pods, nil
+}
+
+// StreamLogs opens a log stream for a pod
+func (c *KubeClient) StreamLogs(ctx context.Context, namespace, pod string, opts LogOptions) (io.ReadCloser, error) {
+	podLogOptions := &corev1.PodLogOptions{
+		Container:  opts.Container,
+		Previous:   opts.Previous,
+		Follow:     opts.Follow,
+		Timestamps: opts.Timestamps,
+	}
+	if opts.Since > 0 {
+		seconds := int64(opts.Since.Seconds())
+		podLogOptions.SinceSeconds = &seconds
+	}
+	if !opts.SinceTime.IsZero() {
+		sinceTime := metav1.NewTime(opts.SinceTime)
+		podLogOptions.SinceTime = &sinceTime
+	}
+
+	return c.clientset.CoreV1().Pods(namespace).GetLogs(pod, podLogOptions).Stream(ctx)
+}
+
+// ListEvents returns the events of a namespace, optionally restricted to
+// those whose involved object has the given name
+func (c *KubeClient) ListEvents(ctx context.Context, namespace, object string) ([]corev1.Event, error) {
+	listOptions := metav1.ListOptions{}
+	if object != "" {
+		listOptions.FieldSelector = fields.OneTermEqualSelector("involvedObject.name", object).String()
+	}
+
+	list, err := c.clientset.CoreV1().Events(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+// ListReplicaSets returns the replica sets of a namespace
+func (c *KubeClient) ListReplicaSets(ctx context.Context, namespace string) ([]appsv1.ReplicaSet, error) {
+	list, err := c.clientset.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
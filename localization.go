@@ -15,6 +15,8 @@ type Localization struct {
 	LogAnalysisTitle        string
 	NamespaceTitle          string
 	Pods                    string
+	ContainersTitle         string
+	AllContainers           string
 
 	// Pod states
 	PodDetails   string
@@ -28,11 +30,111 @@ type Localization struct {
 	TotalLines   string
 	Errors       string
 	Warnings     string
+	MatchedRules string
+	LogFormats   string
+	StackTraces  string
 	LogLines     string
 	ShowingLines string
 	TotalFrom    string
 	LastLines    string
 
+	// Error signatures
+	TopSignatures string
+	Count         string
+	FirstSeen     string
+	LastSeen      string
+	Signature     string
+	Example       string
+
+	// Category tabs
+	TabAll          string
+	TabInfo         string
+	TabUnclassified string
+	Context         string
+	NoLines         string
+	TabControls     string
+
+	// Search
+	RegexMode      string
+	FilterMode     string
+	InvalidRegex   string
+	Matches        string
+	SearchControls string
+
+	// Kubeconfig contexts
+	ContextSelectionTitle string
+	ContextNotFound       string
+	ActiveContext         string
+	Production            string
+	ProductionWarning     string
+	SelectContext         string
+	ContextPicker         string
+	Clusters              string
+
+	// Workload groups
+	Standalone       string
+	Replicas         string
+	AnalyzeWorkload  string
+	CollapseGroups   string
+	GroupByWorkload  string
+	AllPods          string
+	Pod              string
+	ReplicaBreakdown string
+	ScannedPods      string
+	AnalyzeAll       string
+
+	// Pod selector
+	Selector     string
+	SelectorHelp string
+	FilterPods   string
+
+	// Name filter and sorting
+	Filter         string
+	FilterHelp     string
+	FilterNames    string
+	SortBy         string
+	UnhealthyFirst string
+	CycleSort      string
+
+	// Container instances
+	LastTermination  string
+	Reason           string
+	ExitCode         string
+	Signal           string
+	FinishedAt       string
+	CurrentInstance  string
+	PreviousInstance string
+
+	// Events
+	Events    string
+	NoEvents  string
+	EventType string
+	Message   string
+
+	// Timeline
+	Timeline         string
+	Lines            string
+	Column           string
+	TimelineControls string
+
+	// Diff
+	Diff          string
+	DiffHelp      string
+	DiffPods      string
+	DiffChanged   string
+	DiffNew       string
+	DiffGone      string
+	NoDifferences string
+	Now           string
+
+	// Time range
+	TimeWindow     string
+	Custom         string
+	TimePickerHelp string
+	PickTime       string
+	BaseTimeWindow string
+	PickBaseTime   string
+
 	// Status messages
 	NamespaceNotFound string
 	PodNotFound       string
@@ -47,13 +149,26 @@ type Localization struct {
 	Controls          string
 	Movement          string
 	Select            string
+	SelectContainer   string
 	ViewLogs          string
 	GoBack            string
 	Refresh           string
 	AutoRefresh       string
 	AutoRefreshStatus string
+	Every             string
+	Off               string
+	LastRefreshed     string
+	Ago               string
+	RefreshFailed     string
 	Exit              string
 	RefreshLogs       string
+	FollowLogs        string
+	TogglePrevious    string
+	TraceControls     string
+	ScrollControls    string
+	GoToLine          string
+	Position          string
+	Following         string
 	UpDown            string
 	LeftRight         string
 	ScrollUp          string
@@ -73,6 +188,8 @@ func GetLocalization(lang Language) Localization {
 			LogAnalysisTitle:        "Log Analizi",
 			NamespaceTitle:          "Namespace",
 			Pods:                    "Pod'lar",
+			ContainersTitle:         "Container Seçimi",
+			AllContainers:           "Tüm container'lar",
 
 			// Pod states
 			PodDetails:   "Pod Detayları",
@@ -86,11 +203,111 @@ func GetLocalization(lang Language) Localization {
 			TotalLines:   "Toplam satır",
 			Errors:       "Hatalar",
 			Warnings:     "Uyarılar",
+			MatchedRules: "Eşleşen kurallar",
+			LogFormats:   "Log formatları",
+			StackTraces:  "Stack trace'ler",
 			LogLines:     "Log Satırları",
 			ShowingLines: "satır gösteriliyor",
 			TotalFrom:    "Toplam",
 			LastLines:    "satırdan son",
 
+			// Error signatures
+			TopSignatures: "En sık hata imzaları",
+			Count:         "Adet",
+			FirstSeen:     "İlk görülme",
+			LastSeen:      "Son görülme",
+			Signature:     "İmza",
+			Example:       "örnek",
+
+			// Category tabs
+			TabAll:          "Tümü",
+			TabInfo:         "Bilgi",
+			TabUnclassified: "Sınıflandırılmamış",
+			Context:         "Bağlam",
+			NoLines:         "Bu sekmede satır yok",
+			TabControls:     "Tab/1-5: Sekme değiştir, Enter: Satırı bağlamında aç, +/-: Bağlam satır sayısı",
+
+			// Search
+			RegexMode:      "regex",
+			FilterMode:     "filtre",
+			InvalidRegex:   "Geçersiz regex",
+			Matches:        "eşleşme",
+			SearchControls: "/: Ara (Ctrl+R regex), n/N: Sonraki/önceki eşleşme, F: Yalnızca eşleşenler",
+
+			// Kubeconfig contexts
+			ContextSelectionTitle: "Kubernetes Context Seçimi",
+			ContextNotFound:       "Kubeconfig içinde context bulunamadı",
+			ActiveContext:         "aktif",
+			Production:            "üretim",
+			ProductionWarning:     "ÜRETİM ORTAMI",
+			SelectContext:         "Enter: Context'e geç",
+			ContextPicker:         "Esc/Backspace: Context seçimi",
+			Clusters:              "Kümeler",
+
+			// Workload groups
+			Standalone:       "Bağımsız podlar",
+			Replicas:         "Replika",
+			AnalyzeWorkload:  "Grup başlığında Enter: Tüm workload'u analiz et",
+			CollapseGroups:   "z/Z: Grubu/tüm grupları daralt veya genişlet",
+			GroupByWorkload:  "w: Workload gruplamasını aç/kapat",
+			AllPods:          "Tüm podlar",
+			Pod:              "Pod",
+			ReplicaBreakdown: "Replika dağılımı",
+			ScannedPods:      "Hata satırları yalnızca ilk podlar için sayıldı",
+			AnalyzeAll:       "A: Listelenen tüm podları birlikte analiz et",
+
+			// Pod selector
+			Selector:     "Seçici",
+			SelectorHelp: "(örn. app=api,tier!=canary,status.phase!=Running; Enter: uygula, Esc: iptal)",
+			FilterPods:   "s: Podları etiket/alan seçicisiyle filtrele",
+
+			// Name filter and sorting
+			Filter:         "Filtre",
+			FilterHelp:     "(Enter: tamam, Esc: temizle)",
+			FilterNames:    "/: İsme göre bulanık filtrele",
+			SortBy:         "Sıralama",
+			UnhealthyFirst: "Önce sağlıksızlar",
+			CycleSort:      "o: Sıralamayı değiştir",
+
+			// Container instances
+			LastTermination:  "Son sonlanma",
+			Reason:           "Neden",
+			ExitCode:         "çıkış kodu",
+			Signal:           "sinyal",
+			FinishedAt:       "bitiş",
+			CurrentInstance:  "Güncel instance",
+			PreviousInstance: "Önceki instance",
+
+			// Events
+			Events:    "Olaylar",
+			NoEvents:  "Olay yok",
+			EventType: "Tür",
+			Message:   "Mesaj",
+
+			// Timeline
+			Timeline:         "Zaman çizelgesi",
+			Lines:            "Satırlar",
+			Column:           "sütun",
+			TimelineControls: "←/→: Zaman çizelgesinde gez, seçilen aralığın loglarına git",
+
+			// Diff
+			Diff:          "Fark",
+			DiffHelp:      "(d: başka bir pod ile ya da aynı pod ile önceki zaman aralığına göre karşılaştır, Esc: iptal)",
+			DiffPods:      "d: İki pod'un ya da bir pod'un iki zaman aralığının hata imzalarını karşılaştır",
+			DiffChanged:   "Sıklığı değişen",
+			DiffNew:       "Yeni",
+			DiffGone:      "Kaybolan",
+			NoDifferences: "Hata imzalarında önemli bir fark yok",
+			Now:           "şimdi",
+
+			// Time range
+			TimeWindow:     "Zaman aralığı",
+			Custom:         "özel",
+			TimePickerHelp: "←/→: seç, özel aralık: 2h, 2024-05-01T10:00:00Z ya da başlangıç..bitiş, Enter: uygula, Esc: iptal",
+			PickTime:       "T: Zaman aralığını değiştir",
+			BaseTimeWindow: "Sol tarafın zaman aralığı",
+			PickBaseTime:   "b: Sol tarafın zaman aralığını değiştir",
+
 			// Status messages
 			NamespaceNotFound: "Namespace bulunamadı",
 			PodNotFound:       "Pod bulunamadı",
@@ -105,13 +322,26 @@ func GetLocalization(lang Language) Localization {
 			Controls:          "Kontroller",
 			Movement:          "k/j veya ok tuşları: Hareket",
 			Select:            "Enter: Namespace seç",
+			SelectContainer:   "Enter: Container seç",
 			ViewLogs:          "Enter: Log görüntüle",
 			GoBack:            "Esc/Backspace: Geri dön",
 			Refresh:           "r: Yenile",
 			AutoRefresh:       "t: Auto-refresh",
 			AutoRefreshStatus: "Otomatik yenileme",
+			Every:             "her",
+			Off:               "kapalı",
+			LastRefreshed:     "son yenileme",
+			Ago:               "önce",
+			RefreshFailed:     "yenileme başarısız, tekrar denenecek",
 			Exit:              "q: Çıkış",
 			RefreshLogs:       "r: Logları yenile",
+			FollowLogs:        "f: Canlı takip aç/kapat",
+			TogglePrevious:    "p: Güncel/önceki instance logları",
+			ScrollControls:    "PgUp/PgDn: Sayfa, Ctrl+U/Ctrl+D: Yarım sayfa, g/G: Başa/sona git, :: Satıra git",
+			GoToLine:          "satıra git",
+			Position:          "Satırlar",
+			TraceControls:     "[/]: Önceki/sonraki stack trace, x: Aç/kapat, X: Tümünü aç/kapat",
+			Following:         "Canlı takip",
 			UpDown:            "Yukarı/Aşağı: k/j veya ok tuşları",
 			LeftRight:         "Sol/Sağ: h/l veya ok tuşları",
 			ScrollUp:          "Yukarı kaydır",
@@ -127,6 +357,8 @@ func GetLocalization(lang Language) Localization {
 			LogAnalysisTitle:        "Log Analysis",
 			NamespaceTitle:          "Namespace",
 			Pods:                    "Pods",
+			ContainersTitle:         "Container Selection",
+			AllContainers:           "All containers",
 
 			// Pod states
 			PodDetails:   "Pod Details",
@@ -140,11 +372,111 @@ func GetLocalization(lang Language) Localization {
 			TotalLines:   "Total lines",
 			Errors:       "Errors",
 			Warnings:     "Warnings",
+			MatchedRules: "Matched rules",
+			LogFormats:   "Log formats",
+			StackTraces:  "Stack traces",
 			LogLines:     "Log Lines",
 			ShowingLines: "lines showing",
 			TotalFrom:    "Total",
 			LastLines:    "last lines from",
 
+			// Error signatures
+			TopSignatures: "Top error signatures",
+			Count:         "Count",
+			FirstSeen:     "First seen",
+			LastSeen:      "Last seen",
+			Signature:     "Signature",
+			Example:       "e.g.",
+
+			// Category tabs
+			TabAll:          "All",
+			TabInfo:         "Info",
+			TabUnclassified: "Unclassified",
+			Context:         "Context",
+			NoLines:         "No lines in this tab",
+			TabControls:     "Tab/1-5: Switch tab, Enter: Open line in context, +/-: Context lines",
+
+			// Search
+			RegexMode:      "regex",
+			FilterMode:     "filter",
+			InvalidRegex:   "Invalid regex",
+			Matches:        "matches",
+			SearchControls: "/: Search (Ctrl+R regex), n/N: Next/previous match, F: Only matching lines",
+
+			// Kubeconfig contexts
+			ContextSelectionTitle: "Kubernetes Context Selection",
+			ContextNotFound:       "No contexts found in the kubeconfig",
+			ActiveContext:         "active",
+			Production:            "production",
+			ProductionWarning:     "PRODUCTION CLUSTER",
+			SelectContext:         "Enter: Switch to context",
+			ContextPicker:         "Esc/Backspace: Context selection",
+			Clusters:              "Clusters",
+
+			// Workload groups
+			Standalone:       "Standalone pods",
+			Replicas:         "Replicas",
+			AnalyzeWorkload:  "Enter on a group header: Analyze the whole workload",
+			CollapseGroups:   "z/Z: Collapse or expand the group/all groups",
+			GroupByWorkload:  "w: Toggle grouping by workload",
+			AllPods:          "All pods",
+			Pod:              "Pod",
+			ReplicaBreakdown: "Replica breakdown",
+			ScannedPods:      "Error lines counted for the first pods only",
+			AnalyzeAll:       "A: Analyze all listed pods together",
+
+			// Pod selector
+			Selector:     "Selector",
+			SelectorHelp: "(e.g. app=api,tier!=canary,status.phase!=Running; Enter: apply, Esc: cancel)",
+			FilterPods:   "s: Filter pods by label/field selector",
+
+			// Name filter and sorting
+			Filter:         "Filter",
+			FilterHelp:     "(Enter: done, Esc: clear)",
+			FilterNames:    "/: Fuzzy filter by name",
+			SortBy:         "Sort",
+			UnhealthyFirst: "Unhealthy first",
+			CycleSort:      "o: Change sort order",
+
+			// Container instances
+			LastTermination:  "Last termination",
+			Reason:           "Reason",
+			ExitCode:         "exit code",
+			Signal:           "signal",
+			FinishedAt:       "finished",
+			CurrentInstance:  "Current instance",
+			PreviousInstance: "Previous instance",
+
+			// Events
+			Events:    "Events",
+			NoEvents:  "No events",
+			EventType: "Type",
+			Message:   "Message",
+
+			// Timeline
+			Timeline:         "Timeline",
+			Lines:            "Lines",
+			Column:           "column",
+			TimelineControls: "←/→: Move through the timeline and jump to the logs of that range",
+
+			// Diff
+			Diff:          "Diff",
+			DiffHelp:      "(d: compare with another pod, or with the previous window on the same pod, Esc: cancel)",
+			DiffPods:      "d: Compare the error signatures of two pods, or of two windows of one pod",
+			DiffChanged:   "Changed in frequency",
+			DiffNew:       "New",
+			DiffGone:      "Gone",
+			NoDifferences: "No significant difference in error signatures",
+			Now:           "now",
+
+			// Time range
+			TimeWindow:     "Time range",
+			Custom:         "custom",
+			TimePickerHelp: "←/→: choose, custom range: 2h, 2024-05-01T10:00:00Z or start..end, Enter: apply, Esc: cancel",
+			PickTime:       "T: Change the time range",
+			BaseTimeWindow: "Time range of the left side",
+			PickBaseTime:   "b: Change the time range of the left side",
+
 			// Status messages
 			NamespaceNotFound: "Namespace not found",
 			PodNotFound:       "Pod not found",
@@ -159,13 +491,26 @@ func GetLocalization(lang Language) Localization {
 			Controls:          "Controls",
 			Movement:          "k/j or arrow keys: Move",
 			Select:            "Enter: Select namespace",
+			SelectContainer:   "Enter: Select container",
 			ViewLogs:          "Enter: View logs",
 			GoBack:            "Esc/Backspace: Go back",
 			Refresh:           "r: Refresh",
 			AutoRefresh:       "t: Auto-refresh",
 			AutoRefreshStatus: "Auto-refresh",
+			Every:             "every",
+			Off:               "off",
+			LastRefreshed:     "last refreshed",
+			Ago:               "ago",
+			RefreshFailed:     "refresh failed, retrying",
 			Exit:              "q: Exit",
 			RefreshLogs:       "r: Refresh logs",
+			FollowLogs:        "f: Toggle live follow",
+			TogglePrevious:    "p: Toggle current/previous instance logs",
+			ScrollControls:    "PgUp/PgDn: Page, Ctrl+U/Ctrl+D: Half page, g/G: Top/bottom, :: Go to line",
+			GoToLine:          "go to line",
+			Position:          "Lines",
+			TraceControls:     "[/]: Previous/next stack trace, x: Expand/collapse, X: Expand/collapse all",
+			Following:         "Following",
 			UpDown:            "Up/Down: k/j or arrow keys",
 			LeftRight:         "Left/Right: h/l or arrow keys",
 			ScrollUp:          "Scroll up",
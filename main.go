@@ -3,14 +3,34 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
+	// Headless subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "report":
+			os.Exit(runReport(os.Args[2:]))
+		case "check":
+			os.Exit(runCheck(os.Args[2:]))
+		}
+	}
+
 	namespace := ""
-	since := "5m"
+	since := ""
+	sinceTime := ""
+	until := ""
+	rulesPath := ""
+	refresh := defaultRefreshInterval.String()
+	var kubeOptions KubeOptions
+	contexts := ""
+	selector := ""
+	sortMode := sortNames[SortUnhealthy]
+	fieldSelector := ""
 	language := LangEnglish // Default to English
 
 	// Check for command line arguments
@@ -21,26 +41,89 @@ func main() {
 				fmt.Println("Kubernetes Pod Log Analyzer")
 				fmt.Println("Usage:")
 				fmt.Println("  k8s-pod-log-analyzer [options]")
+				fmt.Println("  k8s-pod-log-analyzer report [report options]")
+				fmt.Println("  k8s-pod-log-analyzer check [check options]")
+				fmt.Println("")
+				fmt.Println("Commands:")
+				fmt.Println("  report                       Analyze every pod of a namespace and write a JSON, Markdown or HTML report")
+				fmt.Println("                               (see: k8s-pod-log-analyzer report -h)")
+				fmt.Println("  check                        Fail with a non-zero exit code when pods exceed error/restart thresholds")
+				fmt.Println("                               (see: k8s-pod-log-analyzer check -h)")
 				fmt.Println("")
 				fmt.Println("Options:")
 				fmt.Println("  -n, --namespace <namespace>  Target namespace")
+				fmt.Println("  -l, --selector <selector>    Label selector to filter pods, e.g. app=api")
+				fmt.Println("  --field-selector <selector>  Field selector to filter pods, e.g. status.phase!=Running")
+				fmt.Println("  --context <context>          Kubeconfig context (default: current context)")
+				fmt.Println("  --kubeconfig <file>          Kubeconfig file (default: KUBECONFIG or ~/.kube/config)")
+				fmt.Println("  --contexts <a,b,...>         Show the pods of several contexts side by side")
 				fmt.Println("  -s, --since <duration>       Log duration (default: 5m)")
+				fmt.Println("  --since-time <time>          Analyze from an RFC3339 time or a duration ago, e.g. 2h")
+				fmt.Println("  --until <time>               Drop the lines logged after an RFC3339 time or a duration ago")
 				fmt.Println("  --lang, --language <lang>    Language (en/tr, default: en)")
+				fmt.Println("  --rules <file>               Rules file (default: ~/.config/k8s-pod-log-analyzer/rules.yaml)")
+				fmt.Println("  --refresh <duration>         Auto-refresh interval, 0 disables (default: 5s)")
+				fmt.Println("  --sort <mode>                Pod order: unhealthy, name, status, restarts, age, errors (default: unhealthy)")
 				fmt.Println("  -h, --help                   Show this help")
 				fmt.Println("")
 				fmt.Println("Examples:")
 				fmt.Println("  k8s-pod-log-analyzer --lang tr")
 				fmt.Println("  k8s-pod-log-analyzer -n kube-system --lang en")
 				fmt.Println("  k8s-pod-log-analyzer -n default -s 10m --lang tr")
+				fmt.Println("  k8s-pod-log-analyzer -n shop --since-time 2024-05-01T10:00:00Z --until 2024-05-01T11:00:00Z")
+				fmt.Println("  k8s-pod-log-analyzer --context staging -n default")
+				fmt.Println("  k8s-pod-log-analyzer --contexts prod-eu,prod-us -n shop -l app=api")
+				fmt.Println("  k8s-pod-log-analyzer report -n prod -l app=api --format html -o report.html")
+				fmt.Println("  k8s-pod-log-analyzer check -n prod -s 10m --max-errors 0 --junit report.xml")
 				os.Exit(0)
 			case "-n", "--namespace":
 				if i+2 < len(os.Args) {
 					namespace = os.Args[i+2]
 				}
+			case "-l", "--selector":
+				if i+2 < len(os.Args) {
+					selector = os.Args[i+2]
+				}
+			case "--field-selector":
+				if i+2 < len(os.Args) {
+					fieldSelector = os.Args[i+2]
+				}
+			case "--contexts":
+				if i+2 < len(os.Args) {
+					contexts = os.Args[i+2]
+				}
 			case "-s", "--since":
 				if i+2 < len(os.Args) {
 					since = os.Args[i+2]
 				}
+			case "--since-time":
+				if i+2 < len(os.Args) {
+					sinceTime = os.Args[i+2]
+				}
+			case "--until":
+				if i+2 < len(os.Args) {
+					until = os.Args[i+2]
+				}
+			case "--context":
+				if i+2 < len(os.Args) {
+					kubeOptions.Context = os.Args[i+2]
+				}
+			case "--kubeconfig":
+				if i+2 < len(os.Args) {
+					kubeOptions.Kubeconfig = os.Args[i+2]
+				}
+			case "--rules":
+				if i+2 < len(os.Args) {
+					rulesPath = os.Args[i+2]
+				}
+			case "--sort":
+				if i+2 < len(os.Args) {
+					sortMode = os.Args[i+2]
+				}
+			case "--refresh":
+				if i+2 < len(os.Args) {
+					refresh = os.Args[i+2]
+				}
 			case "--lang", "--language":
 				if i+2 < len(os.Args) {
 					langStr := os.Args[i+2]
@@ -60,16 +143,78 @@ func main() {
 		currentView = "namespaces"
 	}
 
+	window, err := newTimeRange(since, sinceTime, until, time.Now())
+	if err != nil {
+		fmt.Printf("Invalid time range: %v\n", err)
+		os.Exit(1)
+	}
+
+	refreshInterval, err := time.ParseDuration(refresh)
+	if err != nil || refreshInterval < 0 {
+		fmt.Printf("Invalid --refresh value %q\n", refresh)
+		os.Exit(1)
+	}
+
+	podOptions := PodListOptions{LabelSelector: selector, FieldSelector: fieldSelector}
+	if err := podOptions.Validate(); err != nil {
+		fmt.Printf("Invalid selector: %v\n", err)
+		os.Exit(1)
+	}
+
+	podSort, ok := parseSortMode(sortMode)
+	if !ok {
+		fmt.Printf("Invalid --sort value %q (expected %s)\n", sortMode, strings.Join(sortNames, ", "))
+		os.Exit(1)
+	}
+
+	rules, err := LoadRuleConfig(rulesPath)
+	if err != nil {
+		fmt.Printf("Invalid rules:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	contextConfig, err := LoadContextConfig()
+	if err != nil {
+		fmt.Printf("Invalid contexts file:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	var clusters []cluster
+	if contexts != "" {
+		clusters, err = newClusters(kubeOptions.Kubeconfig, contexts)
+		if err != nil {
+			fmt.Printf("Hata: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	client, err := NewKubeClient(kubeOptions)
+	if err != nil {
+		fmt.Printf("Hata: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Get localization for selected language
 	localization := GetLocalization(language)
 
 	m := Model{
+		client:       client,
+		kubeOptions:  kubeOptions,
+		kubeContext:  client.Context(),
+		contextConf:  contextConfig,
+		clusters:     clusters,
+		podOptions:   podOptions,
+		sortMode:     podSort,
+		rules:        rules,
 		namespace:    namespace,
-		since:        since,
+		window:       window,
 		logs:         make(map[string]LogAnalysis),
+		events:       make(map[string]targetEvents),
+		previousLogs: make(map[string]LogAnalysis),
 		currentView:  currentView,
 		loading:      true,
-		autoRefresh:  true,
+		autoRefresh:  refreshInterval > 0,
+		refresh:      refreshInterval,
 		logOffset:    0,
 		language:     language,
 		localization: localization,
@@ -87,15 +232,13 @@ func (m Model) Init() tea.Cmd {
 	var cmds []tea.Cmd
 
 	if m.currentView == "namespaces" {
-		cmds = append(cmds, LoadNamespaces())
+		cmds = append(cmds, m.loadNamespaces())
 	} else {
-		cmds = append(cmds, LoadPods(m.namespace))
+		cmds = append(cmds, m.loadPods())
 	}
 
-	// Start auto-refresh ticker
-	if m.autoRefresh {
-		cmds = append(cmds, Tick())
-	}
+	// Start the ticker driving auto-refresh and the blinking indicators
+	cmds = append(cmds, Tick())
 
 	return tea.Batch(cmds...)
 }
@@ -111,14 +254,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case TickMsg:
 		m.blinkState = !m.blinkState
 
-		// Auto-refresh every 5 seconds
-		if time.Since(time.Time(msg)).Seconds() >= 5 {
-			var cmd tea.Cmd
-			if m.currentView == "namespaces" {
-				cmd = LoadNamespaces()
-			} else if m.currentView == "pods" {
-				cmd = LoadPods(m.namespace)
-			}
+		if cmd := m.scheduleRefresh(time.Time(msg)); cmd != nil {
 			return m, tea.Batch(Tick(), cmd)
 		}
 		return m, Tick()
@@ -126,31 +262,153 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		return m.handleKeyMsg(msg)
 
+	case LoadContextsMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+		} else {
+			m.kubeContexts = msg.contexts
+			m.err = nil
+			// Start on the active context, or the kubeconfig's current one
+			active := m.kubeContext
+			if active == "" {
+				active = msg.current
+			}
+			m.selectedKube = 0
+			for i, name := range m.kubeContexts {
+				if name == active {
+					m.selectedKube = i
+				}
+			}
+		}
+
+	case SwitchContextMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.err = nil
+		return m, m.switchContext(msg)
+
 	case LoadNamespacesMsg:
 		m.loading = false
+		background := m.refreshing
+		m.refreshed(msg.err)
+		if msg.err != nil && background {
+			// Keep the last namespaces on screen and retry with backoff
+			return m, nil
+		}
 		if msg.err != nil {
 			m.err = msg.err
 		} else {
-			m.namespaces = msg.namespaces
+			m.setNamespaces(msg.namespaces)
 			m.err = nil
 		}
 
 	case LoadPodsMsg:
 		m.loading = false
+		background := m.refreshing
+		m.refreshed(msg.err)
+		if msg.err != nil && background {
+			// Keep the last pods on screen and retry with backoff
+			return m, nil
+		}
 		if msg.err != nil {
 			m.err = msg.err
 		} else {
-			m.pods = msg.pods
+			m.setPods(msg.pods)
+			m.clusterErrs = msg.clusterErrs
 			m.err = nil
+			if m.scans == 0 {
+				return m, m.scanPods()
+			}
 		}
 
+	case ScanPodsMsg:
+		m.scans = max(0, m.scans-1)
+		if msg.window != m.window {
+			// Counted in the time range before the picker changed it
+			return m, nil
+		}
+		if m.podErrors == nil {
+			m.podErrors = make(map[string]podScan)
+		}
+		for key, scan := range msg.scans {
+			// An analysis opened meanwhile is what the user saw
+			if old := m.podErrors[key]; old.analyzed && old.restarts == scan.restarts {
+				continue
+			}
+			m.podErrors[key] = scan
+		}
+		// Error counts take part in the sort order
+		m.applyPodFilter()
+
 	case LoadLogsMsg:
+		m.loading = false
+		m.refreshed(msg.err)
 		if msg.err != nil {
 			m.err = msg.err
 		} else {
-			m.logs[msg.pod] = msg.analysis
+			key := msg.target.key()
+			m.logs[key] = msg.analysis
+			m.setEvents(key, msg.events)
+			m.recordAnalysis(msg.target, msg.analysis)
+			if msg.previous != nil {
+				m.previousLogs[key] = *msg.previous
+			} else {
+				delete(m.previousLogs, key)
+			}
+			// A crash-looping container usually has nothing to show yet
+			if m.currentView != "analysis" {
+				m.showPrevious = msg.previous != nil && msg.analysis.TotalLines == 0
+			}
 			m.currentView = "analysis"
 			m.err = nil
+			m.updateMatches()
+		}
+
+	case LogLinesMsg:
+		// Ignore batches from a follower that has since been stopped
+		if msg.follower != m.follower {
+			return m, nil
+		}
+		key := msg.follower.target.key()
+		analysis := m.logs[key]
+		from := len(analysis.Entries)
+		for _, line := range msg.lines {
+			analysis.AddLine(line.text, line.at)
+		}
+		m.logs[key] = analysis
+		m.extendMatches(analysis, from)
+		// Keep the viewport in place unless it is pinned to the tail
+		if m.logOffset > 0 {
+			m.logOffset += len(msg.lines)
+		}
+		return m, m.follower.wait()
+
+	case RefreshLogsMsg:
+		m.refreshed(msg.err)
+		if msg.err == nil {
+			m.applyRefreshedLogs(msg)
+		}
+
+	case FollowStoppedMsg:
+		if msg.follower == m.follower {
+			m.follower = nil
+			if msg.err != nil {
+				m.err = msg.err
+			}
+		}
+
+	case DiffMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+		} else {
+			m.diff = &msg.diff
+			m.currentView = "diff"
+			m.err = nil
 		}
 	}
 
@@ -168,12 +426,18 @@ func (m Model) View() string {
 	}
 
 	switch m.currentView {
+	case "contexts":
+		return m.RenderContextsView()
 	case "namespaces":
 		return m.RenderNamespacesView()
 	case "pods":
 		return m.RenderPodsView()
+	case "containers":
+		return m.RenderContainersView()
 	case "analysis":
 		return m.RenderAnalysisView()
+	case "diff":
+		return m.RenderDiffView()
 	default:
 		return m.RenderNamespacesView()
 	}
@@ -181,7 +445,7 @@ func (m Model) View() string {
 
 func (m Model) renderLoadingView() string {
 	loadingText := m.localization.Loading
-	if m.currentView == "namespaces" {
+	if m.currentView == "namespaces" || m.currentView == "contexts" {
 		loadingText = fmt.Sprintf("🔍 %s...", m.localization.Loading)
 	} else if m.currentView == "pods" {
 		loadingText = fmt.Sprintf("🔍 %s %s...", m.namespace, m.localization.Loading)
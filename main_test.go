@@ -0,0 +1,431 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testPod returns a running pod of namespace ns with the given containers
+func testPod(name string, restarts int32, containers ...string) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	for _, container := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: container})
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:         container,
+			Ready:        true,
+			RestartCount: restarts,
+			State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		})
+	}
+	return pod
+}
+
+// newTestModel returns a model showing the pods of namespace ns
+func newTestModel(client ClusterClient) Model {
+	return Model{
+		client:       client,
+		namespace:    "ns",
+		window:       timeRange{since: defaultSince},
+		logs:         make(map[string]LogAnalysis),
+		events:       make(map[string]targetEvents),
+		previousLogs: make(map[string]LogAnalysis),
+		currentView:  "pods",
+		autoRefresh:  true,
+		localization: GetLocalization(LangEnglish),
+		width:        120,
+		height:       50,
+	}
+}
+
+// update feeds a message to the model
+func update(m Model, msg tea.Msg) (Model, tea.Cmd) {
+	next, cmd := m.Update(msg)
+	return next.(Model), cmd
+}
+
+// run executes a command and the commands of the batches it returns
+func run(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for _, cmd := range batch {
+		msgs = append(msgs, run(cmd)...)
+	}
+	return msgs
+}
+
+// settle feeds the messages of a command to the model until no command is
+// left
+func settle(m Model, cmd tea.Cmd) Model {
+	for _, msg := range run(cmd) {
+		var next tea.Cmd
+		m, next = update(m, msg)
+		m = settle(m, next)
+	}
+	return m
+}
+
+// keyMsg returns the message of a key press
+func keyMsg(key string) tea.KeyMsg {
+	switch key {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+}
+
+// analysisModel returns a model showing the analysis of the given lines
+func analysisModel(lines ...string) Model {
+	m := newTestModel(nil)
+	m.setPods([]PodInfo{NewPodInfo(testPod("api", 0, "app"))})
+	m.container = "app"
+	m.currentView = "analysis"
+	m.logs[m.currentTarget().key()] = AnalyzeLogs(strings.Join(lines, "\n"), nil)
+	return m
+}
+
+// loadedModel returns a model showing the pods of the client
+func loadedModel(t *testing.T, client *FakeClient) Model {
+	t.Helper()
+	m := newTestModel(client)
+	m.loading = true
+	m = settle(m, LoadPods(client, "ns", PodListOptions{}))
+	if m.err != nil || len(m.pods) == 0 {
+		t.Fatalf("pods not loaded: %v", m.err)
+	}
+	return m
+}
+
+func TestUpdateKeys(t *testing.T) {
+	client := NewFakeClient()
+	client.AddPod(testPod("api", 0, "app"))
+	client.AddPod(testPod("web", 0, "app", "proxy"))
+	client.SetLogs("ns", "api", "app", false, "started\n")
+
+	tests := []struct {
+		name        string
+		setup       func(m *Model)
+		keys        []string
+		wantView    string
+		wantLoading bool
+	}{
+		{
+			name:     "enter analyzes a pod with one container",
+			keys:     []string{"enter"},
+			wantView: "analysis",
+		},
+		{
+			name:     "enter opens the container picker of a pod with several",
+			keys:     []string{"j", "enter"},
+			wantView: "containers",
+		},
+		{
+			name:     "r in the container picker does not get stuck loading",
+			keys:     []string{"j", "enter", "r"},
+			wantView: "containers",
+		},
+		{
+			name:     "esc in the container picker goes back to the pods",
+			keys:     []string{"j", "enter", "esc"},
+			wantView: "pods",
+		},
+		{
+			name: "esc in the diff view goes back to the pods",
+			setup: func(m *Model) {
+				m.currentView = "diff"
+				m.diff = &podDiff{}
+			},
+			keys:     []string{"esc"},
+			wantView: "pods",
+		},
+		{
+			name:     "the first esc only clears the pod marked for a diff",
+			keys:     []string{"d", "esc"},
+			wantView: "pods",
+		},
+		{
+			name:     "esc in the pods goes back to the namespaces",
+			keys:     []string{"esc"},
+			wantView: "namespaces",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := loadedModel(t, client)
+			if tt.setup != nil {
+				tt.setup(&m)
+			}
+			for _, key := range tt.keys {
+				var cmd tea.Cmd
+				m, cmd = update(m, keyMsg(key))
+				m = settle(m, cmd)
+			}
+
+			if m.currentView != tt.wantView {
+				t.Errorf("view = %q, want %q", m.currentView, tt.wantView)
+			}
+			if m.loading != tt.wantLoading {
+				t.Errorf("loading = %v, want %v", m.loading, tt.wantLoading)
+			}
+			if m.diffBase != nil {
+				t.Errorf("pod %q still marked for a diff", m.diffBase.Name)
+			}
+			if m.err != nil {
+				t.Errorf("err = %v", m.err)
+			}
+		})
+	}
+}
+
+func TestScheduleRefresh(t *testing.T) {
+	client := NewFakeClient()
+	client.AddPod(testPod("api", 0, "app"))
+	client.SetLogs("ns", "api", "app", false, "started\n")
+
+	tests := []struct {
+		name  string
+		setup func(m *Model)
+		want  bool
+	}{
+		{"namespaces", func(m *Model) { m.currentView = "namespaces" }, true},
+		{"pods", func(m *Model) {}, true},
+		{"container picker", func(m *Model) { m.currentView = "containers" }, false},
+		{"analysis", func(m *Model) { m.currentView = "analysis" }, true},
+		{"analysis of an ended time range", func(m *Model) {
+			m.currentView = "analysis"
+			m.window = timeRange{since: time.Hour, until: time.Now().Add(-time.Minute)}
+		}, false},
+		{"auto-refresh off", func(m *Model) { m.autoRefresh = false }, false},
+		{"loading", func(m *Model) { m.loading = true }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := loadedModel(t, client)
+			m, cmd := update(m, keyMsg("enter"))
+			m = settle(m, cmd)
+			m.currentView = "pods"
+			tt.setup(&m)
+
+			if got := m.scheduleRefresh(time.Now().Add(time.Hour)) != nil; got != tt.want {
+				t.Errorf("refresh scheduled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefreshAppendsNewLines(t *testing.T) {
+	start := time.Now().Add(-time.Minute).Truncate(time.Second)
+	stamp := func(offset time.Duration, text string) string {
+		return start.Add(offset).UTC().Format(time.RFC3339Nano) + " " + text + "\n"
+	}
+
+	client := NewFakeClient()
+	client.AddPod(testPod("api", 0, "app"))
+	client.SetLogs("ns", "api", "app", false, stamp(0, "first")+stamp(1200*time.Millisecond, "second"))
+
+	m := loadedModel(t, client)
+	m, cmd := update(m, keyMsg("enter"))
+	m = settle(m, cmd)
+
+	steps := []struct {
+		name      string
+		appended  string
+		wantLines int
+	}{
+		{"nothing new", "", 2},
+		{"a line in the second of the last one", stamp(1700*time.Millisecond, "ERROR third"), 3},
+		{"nothing new again", "", 3},
+		{"lines without timestamps continue theirs", stamp(3*time.Second, "fourth") + "\tcontinued\n", 5},
+	}
+	for _, step := range steps {
+		if step.appended != "" {
+			client.AppendLogs("ns", "api", "app", step.appended)
+		}
+		m.lastAttempt = time.Time{}
+		cmd := m.scheduleRefresh(time.Now())
+		if cmd == nil {
+			t.Fatalf("%s: no refresh scheduled", step.name)
+		}
+		m = settle(m, cmd)
+
+		analysis := m.logs[m.currentTarget().key()]
+		if analysis.TotalLines != step.wantLines {
+			t.Errorf("%s: %d lines, want %d", step.name, analysis.TotalLines, step.wantLines)
+		}
+	}
+	if got := m.logs[m.currentTarget().key()].ErrorCount; got != 1 {
+		t.Errorf("%d errors, want 1", got)
+	}
+}
+
+func TestFollowSkipsAnalyzedLines(t *testing.T) {
+	start := time.Now().Add(-time.Minute).Truncate(time.Second)
+	stamp := func(offset time.Duration, text string) string {
+		return start.Add(offset).UTC().Format(time.RFC3339Nano) + " " + text + "\n"
+	}
+
+	client := NewFakeClient()
+	client.AddPod(testPod("api", 0, "app"))
+	client.SetLogs("ns", "api", "app", false, stamp(0, "first")+stamp(500*time.Millisecond, "second"))
+
+	m := loadedModel(t, client)
+	m, cmd := update(m, keyMsg("enter"))
+	m = settle(m, cmd)
+
+	m, cmd = update(m, keyMsg("f"))
+	if m.follower == nil || cmd == nil {
+		t.Fatal("follow not started")
+	}
+	defer m.stopFollow()
+	// The stream runs until the follow stops, wait returns its first lines
+	batch := cmd().(tea.BatchMsg)
+	go batch[0]()
+
+	client.AppendLogs("ns", "api", "app", stamp(800*time.Millisecond, "third"))
+	m, _ = update(m, batch[1]())
+
+	analysis := m.logs[m.currentTarget().key()]
+	if analysis.TotalLines != 3 {
+		t.Fatalf("%d lines after following, want 3", analysis.TotalLines)
+	}
+	if got := analysis.Entries[2].Message; got != "third" {
+		t.Errorf("followed line = %q, want third", got)
+	}
+}
+
+func TestScanOnlyNewAndRestartedPods(t *testing.T) {
+	newClient := func(restarts int32, pods ...string) *FakeClient {
+		client := NewFakeClient()
+		for _, pod := range pods {
+			client.AddPod(testPod(pod, restarts, "app"))
+			client.SetLogs("ns", pod, "app", false, "ERROR "+pod+"\n")
+		}
+		return client
+	}
+
+	steps := []struct {
+		name        string
+		client      *FakeClient
+		wantScanned []string
+	}{
+		{"first load", newClient(0, "a", "b"), []string{"a", "b"}},
+		{"reload", newClient(0, "a", "b"), nil},
+		{"new pod", newClient(0, "a", "b", "c"), []string{"c"}},
+		{"restarted pods", newClient(1, "a", "b", "c"), []string{"a", "b", "c"}},
+	}
+
+	m := newTestModel(nil)
+	for _, step := range steps {
+		m.client = step.client
+		var cmd tea.Cmd
+		m, cmd = update(m, LoadPods(step.client, "ns", PodListOptions{})())
+
+		var scanned []string
+		for _, msg := range run(cmd) {
+			if scan, ok := msg.(ScanPodsMsg); ok {
+				for key := range scan.scans {
+					scanned = append(scanned, key)
+				}
+				m, _ = update(m, scan)
+			}
+		}
+		if len(scanned) != len(step.wantScanned) {
+			t.Errorf("%s: scanned %q, want %q", step.name, scanned, step.wantScanned)
+		}
+		for _, pod := range step.wantScanned {
+			if _, ok := m.podErrors[podScanKey("", "ns", pod)]; !ok {
+				t.Errorf("%s: %s not scanned", step.name, pod)
+			}
+		}
+		for _, pod := range m.allPods {
+			if got := m.podErrorCount(pod); got != 1 {
+				t.Errorf("%s: %s has %d errors, want 1", step.name, pod.Name, got)
+			}
+		}
+	}
+}
+
+func TestScanSkipsUnreadableAndCapsPods(t *testing.T) {
+	client := NewFakeClient()
+	client.AddPod(testPod("forbidden", 0, "app")) // No logs, so reading them fails
+	for i := range maxScannedPods + 10 {
+		pod := fmt.Sprintf("pod-%02d", i)
+		client.AddPod(testPod(pod, 0, "app"))
+		client.SetLogs("ns", pod, "app", false, "ERROR "+pod+"\n")
+	}
+
+	m := newTestModel(client)
+	scanned := 0
+	for step := range 3 {
+		var cmd tea.Cmd
+		m, cmd = update(m, LoadPods(client, "ns", PodListOptions{})())
+		for _, msg := range run(cmd) {
+			if scan, ok := msg.(ScanPodsMsg); ok {
+				scanned += len(scan.scans)
+				m, _ = update(m, scan)
+			}
+		}
+		if scanned != maxScannedPods {
+			t.Fatalf("reload %d: %d pods scanned, want %d", step, scanned, maxScannedPods)
+		}
+	}
+
+	if got := m.podErrorCount(PodInfo{Name: "forbidden"}); got != -1 {
+		t.Errorf("unreadable pod has %d errors, want -1", got)
+	}
+	if m.scanStatus() == "" {
+		t.Error("capped scan not shown")
+	}
+}
+
+func TestSortByAnalyzedErrors(t *testing.T) {
+	client := NewFakeClient()
+	client.AddPod(testPod("api", 0, "app"))
+	client.AddPod(testPod("web", 0, "app"))
+	client.SetLogs("ns", "api", "app", false, "ERROR one\n")
+	client.SetLogs("ns", "web", "app", false, "ERROR one\nERROR two\n")
+
+	m := loadedModel(t, client)
+	m.sortMode = SortErrors
+	m.applyPodFilter()
+	if m.pods[0].Name != "web" {
+		t.Fatalf("%s sorted first, want web with the most scanned errors", m.pods[0].Name)
+	}
+
+	// Analyzing api finds more errors than its scan did
+	client.AppendLogs("ns", "api", "app", "ERROR two\nERROR three\n")
+	m.selectPod(PodInfo{Name: "api"})
+	m, cmd := update(m, keyMsg("enter"))
+	m = settle(m, cmd)
+	if m.currentView != "analysis" {
+		t.Fatalf("view = %q, want analysis", m.currentView)
+	}
+	if m.pods[0].Name != "api" || m.podErrorCount(m.pods[0]) != 3 {
+		t.Errorf("%s sorted first with %d errors, want api with 3", m.pods[0].Name, m.podErrorCount(m.pods[0]))
+	}
+
+	// A later scan does not override what the user saw
+	m, _ = update(m, ScanPods(client, "ns", m.allPods, m.window, nil)())
+	if got := m.podErrorCount(PodInfo{Name: "api"}); got != 3 {
+		t.Errorf("api has %d errors after a scan, want 3", got)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// taggedLogs is the log output of one source (container or pod) fetched
+// with timestamps enabled
+type taggedLogs struct {
+	tag  string
+	logs string
+}
+
+type timedLine struct {
+	at   time.Time
+	tag  string
+	text string
+}
+
+// analyzeTimedLogs analyzes timestamped log outputs merged by time. Lines
+// are prefixed with their source tag when tag is set, and lines without a
+// timestamp of their own get the one the API reported.
+func analyzeTimedLogs(sources []taggedLogs, tag bool, rules *RuleSet) LogAnalysis {
+	lines := mergeTimedLines(sources)
+	analysis := newLogAnalysis(rules)
+	for _, line := range lines {
+		text := line.text
+		if tag {
+			text = fmt.Sprintf("[%s] %s", line.tag, line.text)
+		}
+		analysis.classify(text, line.at)
+	}
+	analysis.finish()
+	return analysis
+}
+
+// linesAfter returns the lines of timestamped log outputs logged after the
+// given time, or all of them when it is zero, ordered by time and with their
+// timestamps split off. Lines are tagged with their source when tag is set.
+func linesAfter(sources []taggedLogs, after time.Time, tag bool) []timedLine {
+	var lines []timedLine
+	for _, line := range mergeTimedLines(sources) {
+		if !after.IsZero() && !line.at.After(after) {
+			continue
+		}
+		if tag {
+			line.text = fmt.Sprintf("[%s] %s", line.tag, line.text)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// mergeTimedLines splits timestamped log outputs into lines ordered by
+// time. Lines without a leading timestamp inherit the time of the previous
+// line of the same source. The outputs are split on newlines rather than
+// scanned, so no line is too long to keep.
+func mergeTimedLines(sources []taggedLogs) []timedLine {
+	var lines []timedLine
+	for _, source := range sources {
+		logs := strings.TrimSuffix(source.logs, "\n")
+		if logs == "" {
+			continue
+		}
+		var last time.Time
+		for _, line := range strings.Split(logs, "\n") {
+			at, text, ok := splitTimestamp(strings.TrimSuffix(line, "\r"))
+			if ok {
+				last = at
+			} else {
+				at = last
+			}
+			lines = append(lines, timedLine{at: at, tag: source.tag, text: text})
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].at.Before(lines[j].at) })
+	return lines
+}
+
+// trimUntil drops the lines of a timestamped log stream logged at or after
+// until, along with the lines without a timestamp following them
+func trimUntil(logs string, until time.Time) string {
+	for offset := 0; offset < len(logs); {
+		line := logs[offset:]
+		end := strings.IndexByte(line, '\n')
+		if end >= 0 {
+			line = line[:end]
+		}
+		if at, _, ok := splitTimestamp(line); ok && !at.Before(until) {
+			return logs[:offset]
+		}
+		if end < 0 {
+			break
+		}
+		offset += end + 1
+	}
+	return logs
+}
+
+// splitTimestamp separates the RFC3339 timestamp the API prepends to each
+// line when timestamps are requested
+func splitTimestamp(line string) (time.Time, string, bool) {
+	idx := strings.IndexByte(line, ' ')
+	if idx <= 0 {
+		return time.Time{}, line, false
+	}
+
+	at, err := time.Parse(time.RFC3339Nano, line[:idx])
+	if err != nil {
+		return time.Time{}, line, false
+	}
+	return at, line[idx+1:], true
+}
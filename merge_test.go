@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMergeTimedLines(t *testing.T) {
+	long := strings.Repeat("x", 2*1024*1024) // Longer than any scanner buffer
+	sources := []taggedLogs{
+		{tag: "app", logs: "2024-05-01T10:00:00Z first\n" +
+			"2024-05-01T10:00:03Z " + long + "\n" +
+			"\tcontinued\r\n" +
+			"2024-05-01T10:00:05Z last\n"},
+		{tag: "proxy", logs: "2024-05-01T10:00:01Z second\n2024-05-01T10:00:04Z fourth"},
+		{tag: "empty", logs: ""},
+	}
+
+	want := []struct {
+		tag, text string
+		second    int
+	}{
+		{"app", "first", 0},
+		{"proxy", "second", 1},
+		{"app", long, 3},
+		{"app", "\tcontinued", 3},
+		{"proxy", "fourth", 4},
+		{"app", "last", 5},
+	}
+	got := mergeTimedLines(sources)
+	if len(got) != len(want) {
+		t.Fatalf("%d lines, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		at := time.Date(2024, 5, 1, 10, 0, w.second, 0, time.UTC)
+		if got[i].tag != w.tag || got[i].text != w.text || !got[i].at.Equal(at) {
+			t.Errorf("line %d = %s %d bytes at %v, want %s %d bytes at %v", i, got[i].tag, len(got[i].text), got[i].at, w.tag, len(w.text), at)
+		}
+	}
+}
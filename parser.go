@@ -0,0 +1,306 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Log line formats recognized by ParseLogLine
+const (
+	FormatJSON   = "json"
+	FormatLogfmt = "logfmt"
+	FormatKlog   = "klog"
+	FormatPlain  = "plain"
+)
+
+// Normalized log levels
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelInfo    = "info"
+	LevelDebug   = "debug"
+)
+
+// LogEntry is a single log line with the fields extracted from it. Level,
+// Message and Caller are only set for structured formats; Time falls back to
+// the timestamp the API reported for the line.
+type LogEntry struct {
+	Line     int // 1-based line number in the analyzed output
+	Raw      string
+	Format   string
+	Level    string
+	Time     time.Time
+	Message  string
+	Caller   string
+	Category string // error, warning, info or empty when unclassified
+	Trace    int    // 1-based index into LogAnalysis.Traces, 0 when not part of a stack trace
+	Source   string // Tag of the container or pod a merged line came from
+}
+
+// Field names commonly used by structured loggers (zap, logrus, slog, ECS...)
+var (
+	levelKeys   = []string{"level", "lvl", "severity", "log.level", "loglevel", "levelname"}
+	messageKeys = []string{"msg", "message", "log", "event"}
+	timeKeys    = []string{"time", "ts", "timestamp", "@timestamp", "t"}
+	callerKeys  = []string{"caller", "source", "logger", "file"}
+)
+
+// klogPattern matches the klog header: Lmmdd hh:mm:ss.uuuuuu threadid file:line] msg
+var klogPattern = regexp.MustCompile(`^([IWEF])(\d{2})(\d{2}) (\d{2}:\d{2}:\d{2}\.\d+)\s+\d+ ([^\]]+)\] ?(.*)$`)
+
+// ParseLogLine detects the format of a line and extracts its fields. A
+// leading "[container] " tag added by the multi-container view is skipped
+// during detection.
+func ParseLogLine(line string) LogEntry {
+	entry := LogEntry{Raw: line, Format: FormatPlain, Message: line}
+
+	tag, body := splitTag(line)
+	entry.Source = tag
+	body = strings.TrimSpace(body)
+
+	switch {
+	case strings.HasPrefix(body, "{"):
+		parseJSONLine(body, &entry)
+	case klogPattern.MatchString(body):
+		parseKlogLine(body, &entry)
+	case strings.Contains(body, "="):
+		parseLogfmtLine(body, &entry)
+	}
+
+	return entry
+}
+
+// splitTag separates a leading "[container] " tag from the rest of the
+// line, which keeps its indentation
+func splitTag(line string) (string, string) {
+	if strings.HasPrefix(line, "[") {
+		if end := strings.Index(line, "] "); end > 0 && !strings.ContainsAny(line[1:end], " \t") {
+			return line[1:end], line[end+2:]
+		}
+	}
+	return "", line
+}
+
+// parseJSONLine fills entry from a JSON object line
+func parseJSONLine(body string, entry *LogEntry) {
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(body), &fields); err != nil {
+		return
+	}
+
+	entry.Format = FormatJSON
+	// ECS nests the level under "log": {"level": ...}
+	if nested, ok := fields["log"].(map[string]any); ok {
+		if level, ok := nested["level"]; ok {
+			fields["log.level"] = level
+		}
+	}
+
+	get := func(keys []string) any {
+		for _, key := range keys {
+			if value, ok := fields[key]; ok && value != nil {
+				return value
+			}
+		}
+		return nil
+	}
+
+	if level := get(levelKeys); level != nil {
+		entry.Level = NormalizeLevel(fmt.Sprint(level))
+	}
+	if message, ok := get(messageKeys).(string); ok {
+		entry.Message = message
+	}
+	if caller, ok := get(callerKeys).(string); ok {
+		entry.Caller = caller
+	}
+	switch ts := get(timeKeys).(type) {
+	case string:
+		entry.Time = parseLogTime(ts)
+	case float64:
+		// zap's default epoch seconds
+		sec := int64(ts)
+		entry.Time = time.Unix(sec, int64((ts-float64(sec))*1e9))
+	}
+}
+
+// parseKlogLine fills entry from a klog formatted line
+func parseKlogLine(body string, entry *LogEntry) {
+	match := klogPattern.FindStringSubmatch(body)
+
+	entry.Format = FormatKlog
+	switch match[1] {
+	case "E", "F":
+		entry.Level = LevelError
+	case "W":
+		entry.Level = LevelWarning
+	default:
+		entry.Level = LevelInfo
+	}
+	entry.Caller = strings.TrimSpace(match[5])
+	entry.Message = match[6]
+
+	month, _ := strconv.Atoi(match[2])
+	day, _ := strconv.Atoi(match[3])
+	clock, err := time.Parse("15:04:05.999999", match[4])
+	if err == nil {
+		entry.Time = klogTime(time.Month(month), day, clock, time.Now())
+	}
+}
+
+// klogTime dates a klog header, which omits the year, in the year before
+// now when the current one would put it in the future: lines of December
+// read in January. A day of clock skew is tolerated.
+func klogTime(month time.Month, day int, clock, now time.Time) time.Time {
+	date := func(year int) time.Time {
+		return time.Date(year, month, day,
+			clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), time.UTC)
+	}
+	if at := date(now.Year()); !at.After(now.Add(24 * time.Hour)) {
+		return at
+	}
+	return date(now.Year() - 1)
+}
+
+// parseLogfmtLine fills entry from a logfmt line. Lines that do not look
+// like logfmt (no level or msg key) are left as plain text.
+func parseLogfmtLine(body string, entry *LogEntry) {
+	fields := parseLogfmt(body)
+	if len(fields) < 2 {
+		return
+	}
+
+	get := func(keys []string) (string, bool) {
+		for _, key := range keys {
+			if value, ok := fields[key]; ok {
+				return value, true
+			}
+		}
+		return "", false
+	}
+
+	level, hasLevel := get(levelKeys)
+	message, hasMessage := get(messageKeys)
+	if !hasLevel && !hasMessage {
+		return
+	}
+
+	entry.Format = FormatLogfmt
+	if hasLevel {
+		entry.Level = NormalizeLevel(level)
+	}
+	if hasMessage {
+		entry.Message = message
+	}
+	if caller, ok := get(callerKeys); ok {
+		entry.Caller = caller
+	}
+	if ts, ok := get(timeKeys); ok {
+		entry.Time = parseLogTime(ts)
+	}
+}
+
+// parseLogfmt splits key=value pairs, honoring double-quoted values
+func parseLogfmt(s string) map[string]string {
+	fields := make(map[string]string)
+	i := 0
+	for i < len(s) {
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		start := i
+		for i < len(s) && s[i] != '=' && s[i] != ' ' {
+			i++
+		}
+		key := s[start:i]
+		if i >= len(s) || s[i] != '=' {
+			// Bare word: not a key=value pair
+			if key != "" {
+				return nil
+			}
+			continue
+		}
+		i++ // '='
+
+		var value string
+		if i < len(s) && s[i] == '"' {
+			i++
+			var b strings.Builder
+			for i < len(s) && s[i] != '"' {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				b.WriteByte(s[i])
+				i++
+			}
+			i++ // closing quote
+			value = b.String()
+		} else {
+			start = i
+			for i < len(s) && s[i] != ' ' {
+				i++
+			}
+			value = s[start:i]
+		}
+		if key != "" {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+// parseLogTime parses the timestamp layouts used by common loggers
+func parseLogTime(s string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999Z0700", "2006-01-02 15:04:05.999999999", "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// NormalizeLevel maps logger specific level names onto error, warning,
+// info or debug; unknown levels are returned lower-cased
+func NormalizeLevel(level string) string {
+	level = strings.ToLower(strings.TrimSpace(level))
+	switch level {
+	case "error", "err", "eror", "fatal", "panic", "dpanic", "critical", "crit", "alert", "emerg", "emergency", "severe", "e", "f":
+		return LevelError
+	case "warn", "warning", "w":
+		return LevelWarning
+	case "info", "information", "notice", "i":
+		return LevelInfo
+	case "debug", "trace", "verbose", "d", "fine", "finer", "finest":
+		return LevelDebug
+	}
+	// Numeric syslog / bunyan style levels
+	if n, err := strconv.Atoi(level); err == nil {
+		switch {
+		case n >= 50 || (n >= 0 && n <= 3):
+			return LevelError
+		case n >= 40 || n == 4:
+			return LevelWarning
+		default:
+			return LevelInfo
+		}
+	}
+	return level
+}
+
+// levelCategory maps a normalized level onto a rule category, or "" when
+// the level does not determine one
+func levelCategory(level string) string {
+	switch level {
+	case LevelError:
+		return CategoryError
+	case LevelWarning:
+		return CategoryWarning
+	case LevelInfo, LevelDebug:
+		return CategoryInfo
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLogLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want LogEntry // Only the parsed fields are compared
+	}{
+		{
+			name: "plain",
+			line: "something happened",
+			want: LogEntry{Format: FormatPlain, Message: "something happened"},
+		},
+		{
+			name: "json zap",
+			line: `{"level":"error","ts":1714557600.5,"caller":"api/main.go:42","msg":"request failed"}`,
+			want: LogEntry{Format: FormatJSON, Level: LevelError, Message: "request failed", Caller: "api/main.go:42",
+				Time: time.Unix(1714557600, 5e8)},
+		},
+		{
+			name: "json ecs",
+			line: `{"@timestamp":"2024-05-01T10:00:00Z","log":{"level":"WARN"},"message":"disk almost full"}`,
+			want: LogEntry{Format: FormatJSON, Level: LevelWarning, Message: "disk almost full",
+				Time: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "json numeric level",
+			line: `{"level":50,"msg":"bunyan error"}`,
+			want: LogEntry{Format: FormatJSON, Level: LevelError, Message: "bunyan error"},
+		},
+		{
+			name: "invalid json",
+			line: `{"level":"error"`,
+			want: LogEntry{Format: FormatPlain, Message: `{"level":"error"`},
+		},
+		{
+			name: "logfmt",
+			line: `time=2024-05-01T10:00:00Z level=warn msg="slow request" caller=http.go:12`,
+			want: LogEntry{Format: FormatLogfmt, Level: LevelWarning, Message: "slow request", Caller: "http.go:12",
+				Time: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "not logfmt",
+			line: "retrying in 5s with backoff=2",
+			want: LogEntry{Format: FormatPlain, Message: "retrying in 5s with backoff=2"},
+		},
+		{
+			name: "klog",
+			line: "E0501 10:00:00.123456       1 controller.go:114] sync failed",
+			want: LogEntry{Format: FormatKlog, Level: LevelError, Message: "sync failed", Caller: "controller.go:114"},
+		},
+		{
+			name: "tagged",
+			line: "[app] level=info msg=ready",
+			want: LogEntry{Format: FormatLogfmt, Level: LevelInfo, Message: "ready", Source: "app"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseLogLine(tt.line)
+			if got.Format != tt.want.Format || got.Level != tt.want.Level || got.Message != tt.want.Message ||
+				got.Caller != tt.want.Caller || got.Source != tt.want.Source {
+				t.Errorf("ParseLogLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+			// klog times depend on the current year
+			if tt.want.Format != FormatKlog && !got.Time.Equal(tt.want.Time) {
+				t.Errorf("time = %v, want %v", got.Time, tt.want.Time)
+			}
+		})
+	}
+}
+
+func TestKlogTime(t *testing.T) {
+	clock := time.Date(0, 1, 1, 23, 59, 30, 0, time.UTC)
+	tests := []struct {
+		name  string
+		month time.Month
+		day   int
+		now   time.Time
+		want  time.Time
+	}{
+		{"same day", time.May, 1, time.Date(2024, 5, 1, 23, 59, 59, 0, time.UTC),
+			time.Date(2024, 5, 1, 23, 59, 30, 0, time.UTC)},
+		{"December read in January", time.December, 31, time.Date(2025, 1, 1, 0, 0, 10, 0, time.UTC),
+			time.Date(2024, 12, 31, 23, 59, 30, 0, time.UTC)},
+		{"clock skew", time.May, 2, time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 5, 2, 23, 59, 30, 0, time.UTC)},
+		{"leap day", time.February, 29, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 2, 29, 23, 59, 30, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := klogTime(tt.month, tt.day, clock, tt.now); !got.Equal(tt.want) {
+				t.Errorf("klogTime = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeLevel(t *testing.T) {
+	tests := map[string]string{
+		"ERROR":    LevelError,
+		"fatal":    LevelError,
+		"W":        LevelWarning,
+		" warn ":   LevelWarning,
+		"notice":   LevelInfo,
+		"trace":    LevelDebug,
+		"3":        LevelError,
+		"40":       LevelWarning,
+		"30":       LevelInfo,
+		"Critical": LevelError,
+		"custom":   "custom",
+	}
+	for level, want := range tests {
+		if got := NormalizeLevel(level); got != want {
+			t.Errorf("NormalizeLevel(%q) = %q, want %q", level, got, want)
+		}
+	}
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewPodInfo converts a Kubernetes pod into the summary shown in the TUI
+func NewPodInfo(pod corev1.Pod) PodInfo {
+	status, reason := podStatus(pod)
+
+	ready := "False"
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			ready = string(condition.Status)
+			break
+		}
+	}
+
+	containers := podContainers(pod)
+	// Like kubectl, count the restarts of the app and sidecar containers
+	// only: finished init containers and debug containers do not restart
+	restarts := 0
+	for _, container := range containers {
+		if container.Type == ContainerTypeRegular || container.Type == ContainerTypeSidecar {
+			restarts += container.Restarts
+		}
+	}
+
+	return PodInfo{
+		Name:       pod.Name,
+		Status:     status,
+		Reason:     reason,
+		Ready:      ready,
+		Restarts:   strconv.Itoa(restarts),
+		Age:        CalculateAge(pod.CreationTimestamp.Time),
+		Created:    pod.CreationTimestamp.Time,
+		StatusIcon: GetStatusIcon(status, ready),
+		Containers: containers,
+		Workload:   podOwner(pod),
+	}
+}
+
+// podStatus derives the status of a pod the way the STATUS column of
+// kubectl get pods does, along with the message explaining it, such as the
+// waiting reason of the container that keeps the pod from running
+func podStatus(pod corev1.Pod) (string, string) {
+	status := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		status = pod.Status.Reason // Evicted, NodeLost, ...
+	}
+	reason := pod.Status.Message
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != corev1.PodScheduled || condition.Status == corev1.ConditionTrue {
+			continue
+		}
+		if condition.Reason == corev1.PodReasonSchedulingGated {
+			status = corev1.PodReasonSchedulingGated
+		}
+		if reason == "" {
+			reason = condition.Message
+		}
+	}
+
+	initializing := false
+	for i, cs := range pod.Status.InitContainerStatuses {
+		switch {
+		case cs.State.Terminated != nil && cs.State.Terminated.ExitCode == 0:
+			continue
+		case isSidecar(pod, cs.Name) && cs.Started != nil && *cs.Started:
+			continue
+		case cs.State.Terminated != nil:
+			status = "Init:" + terminatedReason(cs.State.Terminated)
+			reason = containerReason(cs, cs.State.Terminated.Message)
+		case cs.State.Waiting != nil && cs.State.Waiting.Reason != "" && cs.State.Waiting.Reason != "PodInitializing":
+			status = "Init:" + cs.State.Waiting.Reason
+			reason = containerReason(cs, cs.State.Waiting.Message)
+		default:
+			status = fmt.Sprintf("Init:%d/%d", i, len(pod.Spec.InitContainers))
+		}
+		initializing = true
+		break
+	}
+
+	if !initializing || podConditionTrue(pod, corev1.PodInitialized) {
+		running := false
+		for i := len(pod.Status.ContainerStatuses) - 1; i >= 0; i-- {
+			cs := pod.Status.ContainerStatuses[i]
+			switch {
+			case cs.State.Waiting != nil && cs.State.Waiting.Reason != "":
+				status = cs.State.Waiting.Reason
+				reason = containerReason(cs, cs.State.Waiting.Message)
+			case cs.State.Terminated != nil:
+				status = terminatedReason(cs.State.Terminated)
+				reason = containerReason(cs, cs.State.Terminated.Message)
+			case cs.Ready && cs.State.Running != nil:
+				running = true
+			}
+		}
+		// A completed container next to running ones does not complete the pod
+		if status == "Completed" && running {
+			status = "NotReady"
+			if podConditionTrue(pod, corev1.PodReady) {
+				status = "Running"
+			}
+		}
+		// Restarts of running containers are otherwise unexplained
+		for _, cs := range pod.Status.ContainerStatuses {
+			if last := cs.LastTerminationState.Terminated; reason == "" && last != nil && cs.State.Running != nil {
+				reason = fmt.Sprintf("%s: last terminated %s, exit code %d", cs.Name, terminatedReason(last), last.ExitCode)
+			}
+		}
+	}
+
+	// A pod that already completed or failed keeps its status while deleted
+	finished := pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+	if pod.DeletionTimestamp != nil {
+		if pod.Status.Reason == "NodeLost" {
+			status = "Unknown"
+		} else if !finished {
+			status = "Terminating"
+		}
+	}
+	if status == "" {
+		status = "Unknown"
+	}
+	return status, reason
+}
+
+// terminatedReason returns the reason a container terminated, or its signal
+// or exit code when it has none
+func terminatedReason(state *corev1.ContainerStateTerminated) string {
+	switch {
+	case state.Reason != "":
+		return state.Reason
+	case state.Signal != 0:
+		return fmt.Sprintf("Signal:%d", state.Signal)
+	default:
+		return fmt.Sprintf("ExitCode:%d", state.ExitCode)
+	}
+}
+
+// containerReason explains the state of a container with its message and,
+// when it keeps restarting, why it last terminated
+func containerReason(cs corev1.ContainerStatus, message string) string {
+	parts := []string{}
+	if message = strings.TrimSpace(message); message != "" {
+		parts = append(parts, message)
+	}
+	if last := cs.LastTerminationState.Terminated; last != nil && cs.State.Terminated == nil {
+		parts = append(parts, fmt.Sprintf("last terminated %s, exit code %d", terminatedReason(last), last.ExitCode))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return cs.Name + ": " + strings.Join(parts, "; ")
+}
+
+// isSidecar reports whether an init container of a pod keeps running
+// alongside its regular containers
+func isSidecar(pod corev1.Pod, name string) bool {
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == name {
+			return c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways
+		}
+	}
+	return false
+}
+
+// podConditionTrue reports whether a condition of a pod is true
+func podConditionTrue(pod corev1.Pod, conditionType corev1.PodConditionType) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podOwner returns the controller of a pod as Kind/name. Pods of a
+// Deployment report their ReplicaSet until resolveWorkloads runs.
+func podOwner(pod corev1.Pod) string {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil {
+		return ""
+	}
+	return owner.Kind + "/" + owner.Name
+}
+
+// resolveWorkloads replaces the ReplicaSet owners of pods with the
+// Deployment owning that ReplicaSet. Without replica sets, for example when
+// listing them is forbidden, the Deployment name is derived from the
+// pod-template-hash suffix instead.
+func resolveWorkloads(pods []PodInfo, items []corev1.Pod, replicaSets []appsv1.ReplicaSet) {
+	owners := make(map[string]string, len(replicaSets))
+	for _, rs := range replicaSets {
+		owners["ReplicaSet/"+rs.Name] = podOwner(corev1.Pod{ObjectMeta: rs.ObjectMeta})
+	}
+
+	for i := range pods {
+		rs, ok := strings.CutPrefix(pods[i].Workload, "ReplicaSet/")
+		if !ok {
+			continue
+		}
+		if owner, found := owners[pods[i].Workload]; found {
+			if owner != "" {
+				pods[i].Workload = owner
+			}
+			continue
+		}
+		if hash := items[i].Labels[appsv1.DefaultDeploymentUniqueLabelKey]; hash != "" {
+			if name, ok := strings.CutSuffix(rs, "-"+hash); ok {
+				pods[i].Workload = "Deployment/" + name
+			}
+		}
+	}
+}
+
+// podContainers lists init, regular and ephemeral containers in that order
+func podContainers(pod corev1.Pod) []ContainerInfo {
+	var containers []ContainerInfo
+
+	for _, c := range pod.Spec.InitContainers {
+		containerType := ContainerTypeInit
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			containerType = ContainerTypeSidecar
+		}
+		containers = append(containers, newContainerInfo(c.Name, containerType, pod.Status.InitContainerStatuses))
+	}
+	for _, c := range pod.Spec.Containers {
+		containers = append(containers, newContainerInfo(c.Name, ContainerTypeRegular, pod.Status.ContainerStatuses))
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		containers = append(containers, newContainerInfo(c.Name, ContainerTypeEphemeral, pod.Status.EphemeralContainerStatuses))
+	}
+
+	return containers
+}
+
+// newContainerInfo builds a ContainerInfo from the matching container status
+func newContainerInfo(name, containerType string, statuses []corev1.ContainerStatus) ContainerInfo {
+	info := ContainerInfo{Name: name, Type: containerType, State: "Waiting"}
+
+	for _, cs := range statuses {
+		if cs.Name != name {
+			continue
+		}
+		info.Ready = cs.Ready
+		info.Restarts = int(cs.RestartCount)
+		info.State = containerStateString(cs.State)
+		info.Started = cs.State.Waiting == nil || cs.RestartCount > 0
+		if last := cs.LastTerminationState.Terminated; last != nil {
+			info.LastTermination = &ContainerTermination{
+				Reason:     last.Reason,
+				ExitCode:   last.ExitCode,
+				Signal:     last.Signal,
+				FinishedAt: last.FinishedAt.Time,
+			}
+		}
+		break
+	}
+
+	return info
+}
+
+// containerStateString summarizes a container state, preferring its reason
+func containerStateString(state corev1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running"
+	case state.Terminated != nil:
+		if state.Terminated.Reason != "" {
+			return state.Terminated.Reason
+		}
+		return "Terminated"
+	case state.Waiting != nil:
+		if state.Waiting.Reason != "" {
+			return state.Waiting.Reason
+		}
+		return "Waiting"
+	default:
+		return "Waiting"
+	}
+}
+
+// logTargetFor returns the log target of a pod for the selected container
+func logTargetFor(pod PodInfo, container string) logTarget {
+	target := logTarget{context: pod.Context, pod: pod.Name, container: container}
+
+	for _, c := range pod.Containers {
+		if container != AllContainers && c.Name != container {
+			continue
+		}
+		if container == AllContainers && !c.Started {
+			continue
+		}
+		target.containers = append(target.containers, c.Name)
+		if c.Restarts > 0 {
+			target.restarted = append(target.restarted, c.Name)
+		}
+	}
+
+	// Pods without container details still get their default container
+	if container != AllContainers && len(target.containers) == 0 {
+		target.containers = []string{container}
+	}
+	return target
+}
+
+// logStream is one container log read for a target
+type logStream struct {
+	pod       string
+	container string
+	tag       string // Prefix of the stream's lines when several are merged
+}
+
+// streams returns the container logs read for a target. Their lines are
+// tagged with the container name, or for a workload with the pod name and
+// the container name when the pod has several.
+func (t logTarget) streams() []logStream {
+	if t.workload == "" {
+		streams := make([]logStream, 0, len(t.containers))
+		for _, container := range t.containers {
+			streams = append(streams, logStream{pod: t.pod, container: container, tag: container})
+		}
+		return streams
+	}
+
+	var streams []logStream
+	for _, replica := range t.replicas {
+		for _, stream := range replica.streams() {
+			if len(replica.containers) == 1 {
+				stream.tag = replica.pod
+			} else {
+				stream.tag = replica.pod + "/" + stream.container
+			}
+			streams = append(streams, stream)
+		}
+	}
+	return streams
+}
+
+// targetContainers returns the containers of a pod covered by a target
+func targetContainers(pod PodInfo, target logTarget) []ContainerInfo {
+	var containers []ContainerInfo
+	for _, c := range pod.Containers {
+		for _, name := range target.containers {
+			if c.Name == name {
+				containers = append(containers, c)
+				break
+			}
+		}
+	}
+	return containers
+}
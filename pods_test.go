@@ -0,0 +1,207 @@
+package main
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodStatus(t *testing.T) {
+	now := metav1.Now()
+	always := corev1.ContainerRestartPolicyAlways
+	started := true
+	terminated := func(reason string, exitCode int32) corev1.ContainerState {
+		return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: reason, ExitCode: exitCode}}
+	}
+	waiting := func(reason, message string) corev1.ContainerState {
+		return corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: message}}
+	}
+
+	tests := []struct {
+		name       string
+		setup      func(pod *corev1.Pod)
+		wantStatus string
+		wantReason string
+	}{
+		{
+			name:       "running",
+			setup:      func(pod *corev1.Pod) {},
+			wantStatus: "Running",
+		},
+		{
+			name: "crash loop",
+			setup: func(pod *corev1.Pod) {
+				cs := &pod.Status.ContainerStatuses[0]
+				cs.Ready = false
+				cs.State = waiting("CrashLoopBackOff", "back-off 5m0s")
+				cs.LastTerminationState = terminated("Error", 1)
+			},
+			wantStatus: "CrashLoopBackOff",
+			wantReason: "app: back-off 5m0s; last terminated Error, exit code 1",
+		},
+		{
+			name: "killed by a signal",
+			setup: func(pod *corev1.Pod) {
+				pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{Signal: 9, ExitCode: 137},
+				}
+			},
+			wantStatus: "Signal:9",
+		},
+		{
+			name: "restarted container running again",
+			setup: func(pod *corev1.Pod) {
+				pod.Status.ContainerStatuses[0].LastTerminationState = terminated("OOMKilled", 137)
+			},
+			wantStatus: "Running",
+			wantReason: "app: last terminated OOMKilled, exit code 137",
+		},
+		{
+			name: "completed container next to a ready one",
+			setup: func(pod *corev1.Pod) {
+				pod.Status.ContainerStatuses[1].State = terminated("Completed", 0)
+				pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+			},
+			wantStatus: "Running",
+		},
+		{
+			name: "completed container next to one not ready",
+			setup: func(pod *corev1.Pod) {
+				pod.Status.ContainerStatuses[1].State = terminated("Completed", 0)
+			},
+			wantStatus: "NotReady",
+		},
+		{
+			name: "init container pulling",
+			setup: func(pod *corev1.Pod) {
+				pod.Status.Phase = corev1.PodPending
+				pod.Spec.InitContainers = []corev1.Container{{Name: "init"}}
+				pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+					{Name: "init", State: waiting("ImagePullBackOff", "pull access denied")},
+				}
+			},
+			wantStatus: "Init:ImagePullBackOff",
+			wantReason: "init: pull access denied",
+		},
+		{
+			name: "second of two init containers running",
+			setup: func(pod *corev1.Pod) {
+				pod.Status.Phase = corev1.PodPending
+				pod.Spec.InitContainers = []corev1.Container{{Name: "migrate"}, {Name: "seed"}}
+				pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+					{Name: "migrate", State: terminated("Completed", 0)},
+					{Name: "seed", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				}
+			},
+			wantStatus: "Init:1/2",
+		},
+		{
+			name: "started sidecar",
+			setup: func(pod *corev1.Pod) {
+				pod.Spec.InitContainers = []corev1.Container{{Name: "mesh", RestartPolicy: &always}}
+				pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+					{Name: "mesh", Started: &started, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				}
+				pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodInitialized, Status: corev1.ConditionTrue}}
+			},
+			wantStatus: "Running",
+		},
+		{
+			name: "scheduling gated",
+			setup: func(pod *corev1.Pod) {
+				pod.Status = corev1.PodStatus{
+					Phase: corev1.PodPending,
+					Conditions: []corev1.PodCondition{{
+						Type:    corev1.PodScheduled,
+						Status:  corev1.ConditionFalse,
+						Reason:  corev1.PodReasonSchedulingGated,
+						Message: "waiting for quota",
+					}},
+				}
+			},
+			wantStatus: corev1.PodReasonSchedulingGated,
+			wantReason: "waiting for quota",
+		},
+		{
+			name: "evicted",
+			setup: func(pod *corev1.Pod) {
+				pod.Status = corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted", Message: "low on memory"}
+			},
+			wantStatus: "Evicted",
+			wantReason: "low on memory",
+		},
+		{
+			name: "running pod being deleted",
+			setup: func(pod *corev1.Pod) {
+				pod.DeletionTimestamp = &now
+			},
+			wantStatus: "Terminating",
+		},
+		{
+			name: "succeeded pod being deleted",
+			setup: func(pod *corev1.Pod) {
+				pod.DeletionTimestamp = &now
+				pod.Status.Phase = corev1.PodSucceeded
+				pod.Status.ContainerStatuses[0].State = terminated("Completed", 0)
+				pod.Status.ContainerStatuses[1].State = terminated("Completed", 0)
+			},
+			wantStatus: "Completed",
+		},
+		{
+			name: "failed pod being deleted",
+			setup: func(pod *corev1.Pod) {
+				pod.DeletionTimestamp = &now
+				pod.Status.Phase = corev1.PodFailed
+				pod.Status.ContainerStatuses[0].State = terminated("Error", 2)
+				pod.Status.ContainerStatuses[1].State = terminated("Completed", 0)
+			},
+			wantStatus: "Error",
+		},
+		{
+			name: "pod of a lost node",
+			setup: func(pod *corev1.Pod) {
+				pod.DeletionTimestamp = &now
+				pod.Status.Reason = "NodeLost"
+			},
+			wantStatus: "Unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := testPod("api", 0, "app", "proxy")
+			tt.setup(&pod)
+
+			status, reason := podStatus(pod)
+			if status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", status, tt.wantStatus)
+			}
+			if reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestNewPodInfoRestarts(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	pod := testPod("api", 2, "app", "proxy") // 2 restarts each
+	pod.Spec.InitContainers = []corev1.Container{
+		{Name: "migrate"},
+		{Name: "mesh", RestartPolicy: &always},
+	}
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+		{Name: "migrate", RestartCount: 5},
+		{Name: "mesh", RestartCount: 1},
+	}
+	pod.Spec.EphemeralContainers = []corev1.EphemeralContainer{
+		{EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger"}},
+	}
+	pod.Status.EphemeralContainerStatuses = []corev1.ContainerStatus{{Name: "debugger", RestartCount: 7}}
+
+	// The app containers and the sidecar, as kubectl counts them
+	if got := NewPodInfo(pod).Restarts; got != "5" {
+		t.Errorf("restarts = %s, want 5", got)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// defaultRefreshInterval is used when --refresh is not given, or when
+	// auto-refresh is turned on with a zero interval
+	defaultRefreshInterval = 5 * time.Second
+	// maxRefreshBackoff caps the delay between failing refreshes
+	maxRefreshBackoff = 5 * time.Minute
+)
+
+// refreshDelay returns the time until the next refresh, doubled for every
+// consecutive failure
+func (m Model) refreshDelay() time.Duration {
+	delay := m.refresh
+	if delay <= 0 {
+		delay = defaultRefreshInterval
+	}
+	for i := 0; i < m.refreshFails && delay < maxRefreshBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRefreshBackoff {
+		return maxRefreshBackoff
+	}
+	return delay
+}
+
+// scheduleRefresh starts a background refresh of the current view when one
+// is due
+func (m *Model) scheduleRefresh(now time.Time) tea.Cmd {
+	if !m.autoRefresh || m.refreshing || m.loading || now.Sub(m.lastAttempt) < m.refreshDelay() {
+		return nil
+	}
+
+	// Not while the container picker is open: the picked pod could move or
+	// disappear under it
+	var cmd tea.Cmd
+	switch m.currentView {
+	case "namespaces":
+		cmd = m.loadNamespaces()
+	case "pods":
+		cmd = m.loadPods()
+	case "analysis":
+		// A followed analysis is already live, and no line is logged in a
+		// time range that has ended
+		if m.follower == nil && len(m.pods) > 0 && !m.window.closed() {
+			target := m.currentTarget()
+			if analysis, ok := m.logs[target.key()]; ok {
+				cmd = RefreshLogs(m.clientFor(target.context), m.namespace, target, m.eventObjects(target), m.window, analysis.LastLogged)
+			}
+		}
+	}
+	if cmd != nil {
+		m.refreshing = true
+	}
+	return cmd
+}
+
+// refreshed records the outcome of a load of the current view
+func (m *Model) refreshed(err error) {
+	m.refreshing = false
+	m.lastAttempt = time.Now()
+	if err != nil {
+		m.refreshFails++
+		return
+	}
+	m.refreshFails = 0
+	m.lastRefresh = m.lastAttempt
+}
+
+// applyRefreshedLogs appends the lines of an incremental refresh to the
+// analysis of their target
+func (m *Model) applyRefreshedLogs(msg RefreshLogsMsg) {
+	key := msg.target.key()
+	analysis, ok := m.logs[key]
+	if !ok {
+		return
+	}
+
+	from := len(analysis.Entries)
+	for _, line := range msg.lines {
+		analysis.AddLine(line.text, line.at)
+	}
+	analysis.AnalyzedAt = msg.at
+	m.logs[key] = analysis
+	m.setEvents(key, msg.events)
+	m.recordAnalysis(msg.target, analysis)
+
+	if !m.showPrevious && key == m.currentTarget().key() {
+		m.extendMatches(analysis, from)
+		// Keep the viewport in place unless it is pinned to the tail
+		if m.logOffset > 0 {
+			m.logOffset += len(msg.lines)
+		}
+	}
+}
+
+// refreshStatus describes the auto-refresh state and when the current view
+// was last refreshed
+func (m Model) refreshStatus() string {
+	status := m.localization.AutoRefreshStatus + ": "
+	if m.autoRefresh {
+		status += fmt.Sprintf("%s %s", m.localization.Every, m.refreshDelay())
+	} else {
+		status += m.localization.Off
+	}
+
+	if !m.lastRefresh.IsZero() {
+		status += fmt.Sprintf(", %s %s %s", m.localization.LastRefreshed,
+			time.Since(m.lastRefresh).Round(time.Second), m.localization.Ago)
+	}
+	if m.refreshFails > 0 {
+		status += ", " + ErrorStyle.Render(fmt.Sprintf("%s (%d)", m.localization.RefreshFailed, m.refreshFails))
+	}
+	return status
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// reportConcurrency bounds how many pods have their logs fetched at once
+const reportConcurrency = 8
+
+// maxTopErrors is the number of most frequent error lines kept per pod
+const maxTopErrors = 10
+
+// Report is the structured result of analyzing every pod of a namespace
+type Report struct {
+	GeneratedAt   time.Time     `json:"generatedAt"`
+	Namespace     string        `json:"namespace"`
+	Selector      string        `json:"selector,omitempty"`
+	FieldSelector string        `json:"fieldSelector,omitempty"`
+	Since         string        `json:"since"`
+	Summary       ReportSummary `json:"summary"`
+	Pods          []PodReport   `json:"pods"`
+}
+
+// ReportSummary aggregates the per-pod counts of a report
+type ReportSummary struct {
+	Pods         int `json:"pods"`
+	NotReadyPods int `json:"notReadyPods"`
+	Restarts     int `json:"restarts"`
+	TotalLines   int `json:"totalLines"`
+	ErrorCount   int `json:"errorCount"`
+	WarningCount int `json:"warningCount"`
+}
+
+// PodReport holds the analysis of a single pod
+type PodReport struct {
+	Name         string      `json:"name"`
+	Status       string      `json:"status"`
+	Reason       string      `json:"reason,omitempty"`
+	Ready        bool        `json:"ready"`
+	Restarts     int         `json:"restarts"`
+	Age          string      `json:"age"`
+	TotalLines   int         `json:"totalLines"`
+	ErrorCount   int         `json:"errorCount"`
+	WarningCount int         `json:"warningCount"`
+	InfoCount    int         `json:"infoCount"`
+	TopErrors    []LineCount `json:"topErrors"`
+	LogError     string      `json:"logError,omitempty"`
+}
+
+// LineCount is a log line with the number of times it occurred
+type LineCount struct {
+	Line  string `json:"line"`
+	Count int    `json:"count"`
+}
+
+// podAnalysis pairs a pod with the analysis of all its containers' logs
+type podAnalysis struct {
+	pod      PodInfo
+	analysis LogAnalysis
+	err      error
+}
+
+// analyzeNamespace lists the pods matching opts and analyzes the logs of
+// every one of them
+func analyzeNamespace(ctx context.Context, client ClusterClient, namespace string, opts PodListOptions, window timeRange, rules *RuleSet) ([]podAnalysis, error) {
+	pods, err := listPodInfos(ctx, client, namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+	return analyzePods(ctx, client, namespace, pods, window, rules), nil
+}
+
+// analyzePods analyzes the logs of all containers of every pod concurrently.
+// A pod whose logs cannot be read is kept with its error instead of failing
+// the whole run.
+func analyzePods(ctx context.Context, client ClusterClient, namespace string, pods []PodInfo, window timeRange, rules *RuleSet) []podAnalysis {
+	results := make([]podAnalysis, len(pods))
+	sem := make(chan struct{}, reportConcurrency)
+	var wg sync.WaitGroup
+	for i, pod := range pods {
+		wg.Add(1)
+		go func(i int, pod PodInfo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i].pod = pod
+			target := logTargetFor(pod, AllContainers)
+			if len(target.containers) == 0 {
+				results[i].analysis = AnalyzeLogs("", rules)
+				return
+			}
+			results[i].analysis, results[i].err = loadContainerLogs(ctx, client, namespace, pod.Name, target.containers, window.logOptions(), rules)
+		}(i, pod)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// BuildReport analyzes a namespace and turns the result into a Report
+func BuildReport(ctx context.Context, client ClusterClient, namespace string, opts PodListOptions, since time.Duration, rules *RuleSet) (Report, error) {
+	results, err := analyzeNamespace(ctx, client, namespace, opts, timeRange{since: since}, rules)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		GeneratedAt:   time.Now(),
+		Namespace:     namespace,
+		Selector:      opts.LabelSelector,
+		FieldSelector: opts.FieldSelector,
+		Since:         since.String(),
+		Pods:          make([]PodReport, 0, len(results)),
+	}
+
+	for _, result := range results {
+		restarts, _ := strconv.Atoi(result.pod.Restarts)
+		podReport := PodReport{
+			Name:         result.pod.Name,
+			Status:       result.pod.Status,
+			Reason:       result.pod.Reason,
+			Ready:        result.pod.Ready == "True",
+			Restarts:     restarts,
+			Age:          result.pod.Age,
+			TotalLines:   result.analysis.TotalLines,
+			ErrorCount:   result.analysis.ErrorCount,
+			WarningCount: result.analysis.WarningCount,
+			InfoCount:    result.analysis.InfoCount,
+			TopErrors:    topLines(result.analysis.Errors, maxTopErrors),
+		}
+		if result.err != nil {
+			podReport.LogError = result.err.Error()
+		}
+
+		report.Pods = append(report.Pods, podReport)
+		report.Summary.Pods++
+		if !podReport.Ready {
+			report.Summary.NotReadyPods++
+		}
+		report.Summary.Restarts += podReport.Restarts
+		report.Summary.TotalLines += podReport.TotalLines
+		report.Summary.ErrorCount += podReport.ErrorCount
+		report.Summary.WarningCount += podReport.WarningCount
+	}
+
+	return report, nil
+}
+
+// topLines counts identical lines and returns the n most frequent ones
+func topLines(lines []string, n int) []LineCount {
+	counts := make(map[string]int)
+	var order []string
+	for _, line := range lines {
+		if counts[line] == 0 {
+			order = append(order, line)
+		}
+		counts[line]++
+	}
+
+	top := make([]LineCount, 0, len(order))
+	for _, line := range order {
+		top = append(top, LineCount{Line: line, Count: counts[line]})
+	}
+	sort.SliceStable(top, func(i, j int) bool { return top[i].Count > top[j].Count })
+
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// runReport implements the `report` subcommand and returns the exit code
+func runReport(args []string) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	namespace := fs.String("namespace", "default", "Namespace to analyze")
+	fs.StringVar(namespace, "n", "default", "Namespace to analyze (shorthand)")
+	selector := fs.String("selector", "", "Label selector to filter pods, e.g. app=api")
+	fs.StringVar(selector, "l", "", "Label selector (shorthand)")
+	fieldSelector := fs.String("field-selector", "", "Field selector to filter pods, e.g. status.phase!=Running")
+	since := fs.Duration("since", 5*time.Minute, "Log duration to analyze")
+	fs.DurationVar(since, "s", 5*time.Minute, "Log duration (shorthand)")
+	format := fs.String("format", "json", "Output format: json, markdown or html")
+	fs.StringVar(format, "f", "json", "Output format (shorthand)")
+	output := fs.String("output", "", "Output file (default: stdout)")
+	fs.StringVar(output, "o", "", "Output file (shorthand)")
+	rulesPath := fs.String("rules", "", "Rules file (default: ~/.config/k8s-pod-log-analyzer/rules.yaml)")
+	var kubeOptions KubeOptions
+	fs.StringVar(&kubeOptions.Context, "context", "", "Kubeconfig context (default: current context)")
+	fs.StringVar(&kubeOptions.Kubeconfig, "kubeconfig", "", "Kubeconfig file (default: KUBECONFIG or ~/.kube/config)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	writeReport, ok := reportWriters[*format]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown report format %q (expected json, markdown or html)\n", *format)
+		return 2
+	}
+
+	podOptions := PodListOptions{LabelSelector: *selector, FieldSelector: *fieldSelector}
+	if err := podOptions.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid selector: %v\n", err)
+		return 2
+	}
+
+	rules, err := LoadRuleConfig(*rulesPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid rules:\n%v\n", err)
+		return 2
+	}
+
+	client, err := NewKubeClient(kubeOptions)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	report, err := BuildReport(ctx, client, *namespace, podOptions, *since, rules.ForNamespace(*namespace))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := writeOutput(*output, func(w io.Writer) error { return writeReport(w, report) }); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// writeOutput runs write against the named file, or stdout when path is empty
+func writeOutput(path string, write func(io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// reportWriters maps a --format value to the function rendering it
+var reportWriters = map[string]func(io.Writer, Report) error{
+	"json":     WriteReportJSON,
+	"markdown": WriteReportMarkdown,
+	"md":       WriteReportMarkdown,
+	"html":     WriteReportHTML,
+}
+
+// WriteReportJSON writes the report as indented JSON
+func WriteReportJSON(w io.Writer, report Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteReportMarkdown writes the report as a Markdown document
+func WriteReportMarkdown(w io.Writer, report Report) error {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("# Pod Log Report: %s\n\n", report.Namespace))
+	b.WriteString(fmt.Sprintf("- Generated: %s\n", report.GeneratedAt.Format("2006-01-02 15:04:05 MST")))
+	if report.Selector != "" {
+		b.WriteString(fmt.Sprintf("- Selector: `%s`\n", report.Selector))
+	}
+	if report.FieldSelector != "" {
+		b.WriteString(fmt.Sprintf("- Field selector: `%s`\n", report.FieldSelector))
+	}
+	b.WriteString(fmt.Sprintf("- Window: last %s\n\n", report.Since))
+
+	b.WriteString("## Summary\n\n")
+	b.WriteString("| Pods | Not ready | Restarts | Lines | Errors | Warnings |\n")
+	b.WriteString("| ---- | --------- | -------- | ----- | ------ | -------- |\n")
+	b.WriteString(fmt.Sprintf("| %d | %d | %d | %d | %d | %d |\n\n",
+		report.Summary.Pods, report.Summary.NotReadyPods, report.Summary.Restarts,
+		report.Summary.TotalLines, report.Summary.ErrorCount, report.Summary.WarningCount))
+
+	b.WriteString("## Pods\n\n")
+	b.WriteString("| Pod | Status | Ready | Restarts | Age | Lines | Errors | Warnings |\n")
+	b.WriteString("| --- | ------ | ----- | -------- | --- | ----- | ------ | -------- |\n")
+	for _, pod := range report.Pods {
+		b.WriteString(fmt.Sprintf("| %s | %s | %t | %d | %s | %d | %d | %d |\n",
+			pod.Name, pod.Status, pod.Ready, pod.Restarts, pod.Age,
+			pod.TotalLines, pod.ErrorCount, pod.WarningCount))
+	}
+	b.WriteString("\n")
+
+	for _, pod := range report.Pods {
+		if len(pod.TopErrors) == 0 && pod.LogError == "" {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("### %s\n\n", pod.Name))
+		if pod.LogError != "" {
+			b.WriteString(fmt.Sprintf("Logs could not be read: %s\n\n", pod.LogError))
+		}
+		if len(pod.TopErrors) > 0 {
+			b.WriteString("| Count | Error |\n")
+			b.WriteString("| ----- | ----- |\n")
+			for _, line := range pod.TopErrors {
+				b.WriteString(fmt.Sprintf("| %d | `%s` |\n", line.Count, markdownCell(line.Line)))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell escapes a log line for use inside a Markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "`", "'")
+}
+
+// WriteReportHTML writes the report as a self-contained HTML page
+func WriteReportHTML(w io.Writer, report Report) error {
+	return reportHTMLTemplate.Execute(w, report)
+}
+
+var reportHTMLTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Pod Log Report: {{.Namespace}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+h1 { color: #7D56F4; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ddd; padding: 4px 10px; text-align: left; vertical-align: top; }
+th { background: #f4f1fe; }
+code { font-family: Menlo, Consolas, monospace; font-size: 0.9em; white-space: pre-wrap; }
+.error { color: #FF5F56; font-weight: bold; }
+.warning { color: #C98A00; font-weight: bold; }
+.ok { color: #28CA42; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Pod Log Report: {{.Namespace}}</h1>
+<p>Generated {{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}{{if .Selector}} &middot; selector <code>{{.Selector}}</code>{{end}}{{if .FieldSelector}} &middot; field selector <code>{{.FieldSelector}}</code>{{end}} &middot; last {{.Since}}</p>
+
+<h2>Summary</h2>
+<table>
+<tr><th>Pods</th><th>Not ready</th><th>Restarts</th><th>Lines</th><th>Errors</th><th>Warnings</th></tr>
+<tr><td>{{.Summary.Pods}}</td><td>{{.Summary.NotReadyPods}}</td><td>{{.Summary.Restarts}}</td><td>{{.Summary.TotalLines}}</td><td class="{{if .Summary.ErrorCount}}error{{else}}ok{{end}}">{{.Summary.ErrorCount}}</td><td class="{{if .Summary.WarningCount}}warning{{else}}ok{{end}}">{{.Summary.WarningCount}}</td></tr>
+</table>
+
+<h2>Pods</h2>
+<table>
+<tr><th>Pod</th><th>Status</th><th>Ready</th><th>Restarts</th><th>Age</th><th>Lines</th><th>Errors</th><th>Warnings</th></tr>
+{{range .Pods}}<tr><td>{{.Name}}</td><td>{{.Status}}</td><td>{{.Ready}}</td><td>{{.Restarts}}</td><td>{{.Age}}</td><td>{{.TotalLines}}</td><td class="{{if .ErrorCount}}error{{else}}ok{{end}}">{{.ErrorCount}}</td><td class="{{if .WarningCount}}warning{{else}}ok{{end}}">{{.WarningCount}}</td></tr>
+{{end}}</table>
+
+{{range .Pods}}{{if or .TopErrors .LogError}}<h3>{{.Name}}</h3>
+{{if .LogError}}<p class="error">Logs could not be read: {{.LogError}}</p>
+{{end}}{{if .TopErrors}}<table>
+<tr><th>Count</th><th>Error</th></tr>
+{{range .TopErrors}}<tr><td>{{.Count}}</td><td><code>{{.Line}}</code></td></tr>
+{{end}}</table>
+{{end}}{{end}}{{end}}
+</body>
+</html>
+`))
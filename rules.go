@@ -0,0 +1,369 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Rule categories, in the order they are evaluated
+const (
+	CategoryError   = "error"
+	CategoryWarning = "warning"
+	CategoryInfo    = "info"
+)
+
+var ruleCategories = []string{CategoryError, CategoryWarning, CategoryInfo}
+
+// Rule severities
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+)
+
+// Rule classifies log lines matching Pattern into Category, unless one of
+// the Exclude patterns also matches. Spans matching an Ignore pattern are
+// blanked out before either is matched.
+type Rule struct {
+	Name     string   `json:"name"`
+	Pattern  string   `json:"pattern"`
+	Category string   `json:"category"`
+	Severity string   `json:"severity,omitempty"`
+	Exclude  []string `json:"exclude,omitempty"`
+	Ignore   []string `json:"ignore,omitempty"`
+	Hint     string   `json:"hint,omitempty"`
+	Disabled bool     `json:"disabled,omitempty"`
+
+	re       *regexp.Regexp
+	excludes []*regexp.Regexp
+	ignores  []*regexp.Regexp
+}
+
+// matches reports whether the rule applies to a line
+func (r *Rule) matches(line string) bool {
+	for _, ignore := range r.ignores {
+		line = ignore.ReplaceAllString(line, " ")
+	}
+	if !r.re.MatchString(line) {
+		return false
+	}
+	for _, exclude := range r.excludes {
+		if exclude.MatchString(line) {
+			return false
+		}
+	}
+	return true
+}
+
+// rulesFile is the on-disk format of a rules file (YAML or JSON)
+type rulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// zeroCounterIgnore blanks out keywords mentioned as a zero valued counter,
+// e.g. `errors=0` or `retry_count: 0`, so only the rest of the line counts
+const zeroCounterIgnore = `(?i)\b\w*(error|err|fail|failed|failure|failures|exception|panic|retry|retries|timeout|timeouts|warn|warning|warnings)\w*["']?\s*[=:]\s*0\b`
+
+// DefaultRules returns the built-in rules used when no rules file overrides them
+func DefaultRules() []Rule {
+	return []Rule{
+		{Name: "error-keywords", Category: CategoryError, Severity: SeverityHigh,
+			Pattern: `(?i)\b(error|err|exception|fatal|panic|crash|failed|failure)\b`,
+			Ignore:  []string{zeroCounterIgnore}},
+		{Name: "stack-trace", Category: CategoryError, Severity: SeverityHigh,
+			Pattern: `(?i)\b(stack\s+trace|stacktrace)\b`},
+		{Name: "connection-failure", Category: CategoryError, Severity: SeverityHigh,
+			Pattern: `(?i)\b(connection\s+(refused|failed|timeout))\b`,
+			Hint:    "Check that the target service is running and reachable (Service endpoints, NetworkPolicy, DNS)"},
+		{Name: "out-of-memory", Category: CategoryError, Severity: SeverityCritical,
+			Pattern: `(?i)\b(out\s+of\s+memory|oom)\b`,
+			Hint:    "Raise the container memory limit or investigate memory growth"},
+		{Name: "permission-denied", Category: CategoryError, Severity: SeverityHigh,
+			Pattern: `(?i)\b(permission\s+denied|access\s+denied)\b`,
+			Hint:    "Check RBAC, ServiceAccount permissions, file permissions and securityContext"},
+		{Name: "warning-keywords", Category: CategoryWarning, Severity: SeverityMedium,
+			Pattern: `(?i)\b(warn|warning|deprecated|timeout|retry|retrying)\b`,
+			Ignore:  []string{zeroCounterIgnore}},
+		{Name: "slow-query", Category: CategoryWarning, Severity: SeverityMedium,
+			Pattern: `(?i)\b(slow\s+query|performance)\b`},
+		{Name: "connection-lost", Category: CategoryWarning, Severity: SeverityMedium,
+			Pattern: `(?i)\b(connection\s+lost|reconnecting)\b`},
+		{Name: "info-keywords", Category: CategoryInfo, Severity: SeverityLow,
+			Pattern: `(?i)\b(info|starting|started|listening|ready|success|successful|completed)\b`},
+		{Name: "lifecycle", Category: CategoryInfo, Severity: SeverityLow,
+			Pattern: `(?i)\b(connected|initialized|loaded)\b`},
+	}
+}
+
+// RuleSet is an ordered, compiled set of rules
+type RuleSet struct {
+	rules  []*Rule
+	byName map[string]*Rule
+}
+
+// newRuleSet compiles rules into a RuleSet ordered by category. The rules
+// must have been validated.
+func newRuleSet(rules []Rule) *RuleSet {
+	set := &RuleSet{byName: make(map[string]*Rule)}
+	for _, category := range ruleCategories {
+		for i := range rules {
+			rule := rules[i]
+			if rule.Disabled || rule.Category != category {
+				continue
+			}
+			if err := rule.compile(); err != nil {
+				continue
+			}
+			set.rules = append(set.rules, &rule)
+			set.byName[rule.Name] = &rule
+		}
+	}
+	return set
+}
+
+// defaultRuleSet is shared by analyses run without explicit rules
+var defaultRuleSet = newRuleSet(DefaultRules())
+
+// Match returns the first rule matching a line, errors before warnings
+// before info, or nil
+func (s *RuleSet) Match(line string) *Rule {
+	for _, rule := range s.rules {
+		if rule.matches(line) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// Rule returns the rule with the given name, or nil
+func (s *RuleSet) Rule(name string) *Rule {
+	return s.byName[name]
+}
+
+// compile compiles the rule's patterns and fills in its default severity
+func (r *Rule) compile() error {
+	re, err := regexp.Compile(r.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	r.re = re
+
+	r.excludes = nil
+	for _, exclude := range r.Exclude {
+		re, err := regexp.Compile(exclude)
+		if err != nil {
+			return fmt.Errorf("invalid exclude pattern %q: %w", exclude, err)
+		}
+		r.excludes = append(r.excludes, re)
+	}
+
+	r.ignores = nil
+	for _, ignore := range r.Ignore {
+		re, err := regexp.Compile(ignore)
+		if err != nil {
+			return fmt.Errorf("invalid ignore pattern %q: %w", ignore, err)
+		}
+		r.ignores = append(r.ignores, re)
+	}
+
+	if r.Severity == "" {
+		switch r.Category {
+		case CategoryError:
+			r.Severity = SeverityHigh
+		case CategoryWarning:
+			r.Severity = SeverityMedium
+		default:
+			r.Severity = SeverityLow
+		}
+	}
+	return nil
+}
+
+// validate checks a single rule; a rule that only disables an existing
+// rule by name may omit the pattern and category
+func (r Rule) validate() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if r.Disabled {
+		return nil
+	}
+	if r.Pattern == "" {
+		return errors.New("pattern is required")
+	}
+	switch r.Category {
+	case CategoryError, CategoryWarning, CategoryInfo:
+	default:
+		return fmt.Errorf("category must be one of %s, got %q", strings.Join(ruleCategories, ", "), r.Category)
+	}
+	switch r.Severity {
+	case "", SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow:
+	default:
+		return fmt.Errorf("severity must be one of critical, high, medium, low, got %q", r.Severity)
+	}
+	return r.compile()
+}
+
+// RuleConfig holds the global rules and the per-namespace overrides loaded
+// from the configuration directory
+type RuleConfig struct {
+	global     []Rule
+	namespaces map[string][]Rule
+	cache      map[string]*RuleSet
+}
+
+// configDir returns the analyzer's configuration directory, following the
+// XDG base directory convention
+func configDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "k8s-pod-log-analyzer")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "k8s-pod-log-analyzer")
+}
+
+// findConfigFile returns the first existing configuration file for a base
+// path in the configuration directory, trying .yaml, .yml and .json
+func findConfigFile(base string) string {
+	for _, ext := range []string{".yaml", ".yml", ".json"} {
+		if _, err := os.Stat(base + ext); err == nil {
+			return base + ext
+		}
+	}
+	return ""
+}
+
+// LoadRuleConfig loads the global rules file and every per-namespace
+// override in the namespaces/ directory next to it. An empty path selects
+// the default location. Missing files are not an error; all validation
+// problems are reported together.
+func LoadRuleConfig(path string) (*RuleConfig, error) {
+	config := &RuleConfig{
+		global:     DefaultRules(),
+		namespaces: make(map[string][]Rule),
+		cache:      make(map[string]*RuleSet),
+	}
+
+	dir := configDir()
+	explicit := path != ""
+	if !explicit && dir != "" {
+		path = findConfigFile(filepath.Join(dir, "rules"))
+	} else if explicit {
+		dir = filepath.Dir(path)
+	}
+
+	var errs []error
+	var err error
+	if path != "" {
+		rules, err := readRulesFile(path)
+		if err != nil && (explicit || !errors.Is(err, os.ErrNotExist)) {
+			errs = append(errs, err)
+		}
+		config.global = mergeRules(config.global, rules)
+	}
+
+	var entries []os.DirEntry
+	if dir != "" {
+		entries, err = os.ReadDir(filepath.Join(dir, "namespaces"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		rules, err := readRulesFile(filepath.Join(dir, "namespaces", entry.Name()))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		namespace := strings.TrimSuffix(entry.Name(), ext)
+		config.namespaces[namespace] = append(config.namespaces[namespace], rules...)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return config, nil
+}
+
+// ForNamespace returns the compiled rules for a namespace: the global rules
+// with that namespace's overrides applied
+func (c *RuleConfig) ForNamespace(namespace string) *RuleSet {
+	if c == nil {
+		return defaultRuleSet
+	}
+	if set, ok := c.cache[namespace]; ok {
+		return set
+	}
+	set := newRuleSet(mergeRules(c.global, c.namespaces[namespace]))
+	c.cache[namespace] = set
+	return set
+}
+
+// readRulesFile parses and validates a YAML or JSON rules file
+func readRulesFile(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file rulesFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var errs []error
+	seen := make(map[string]bool)
+	for i, rule := range file.Rules {
+		if err := rule.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: rule %d (%s): %w", path, i+1, rule.Name, err))
+			continue
+		}
+		if seen[rule.Name] {
+			errs = append(errs, fmt.Errorf("%s: rule %d (%s): duplicate name", path, i+1, rule.Name))
+		}
+		seen[rule.Name] = true
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return file.Rules, nil
+}
+
+// mergeRules applies overrides on top of base: a rule with an existing name
+// replaces it (or removes it when disabled), new rules are appended
+func mergeRules(base, overrides []Rule) []Rule {
+	merged := append([]Rule(nil), base...)
+	for _, override := range overrides {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name == override.Name {
+				if override.Disabled {
+					merged[i].Disabled = true
+				} else {
+					merged[i] = override
+				}
+				replaced = true
+				break
+			}
+		}
+		if !replaced && !override.Disabled {
+			merged = append(merged, override)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDefaultRulesMatch(t *testing.T) {
+	tests := []struct {
+		line string
+		want string // Rule name, "" for no match
+	}{
+		{"ERROR failed to connect", "error-keywords"},
+		{"panic: runtime error", "error-keywords"},
+		{"dial tcp: connection refused", "connection-failure"},
+		{"container killed: out of memory", "out-of-memory"},
+		{"open /data: permission denied", "permission-denied"},
+		{"WARN disk almost full", "warning-keywords"},
+		{"request timeout, retrying", "warning-keywords"},
+		{"slow query took 3s", "slow-query"},
+		{"server started on :8080", "info-keywords"},
+		{"cache initialized", "lifecycle"},
+		{"processed batch errors=0 retries: 0", ""},
+		{"ERROR failed to connect to db retries=0", "error-keywords"},
+		{"request timeout, errors: 0 retried", "warning-keywords"},
+		{`{"msg":"sync done","failures":0}`, ""},
+		{"GET /healthz 200", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got := ""
+			if rule := defaultRuleSet.Match(tt.line); rule != nil {
+				got = rule.Name
+			}
+			if got != tt.want {
+				t.Errorf("Match(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeRules(t *testing.T) {
+	base := []Rule{
+		{Name: "a", Pattern: "a", Category: CategoryError},
+		{Name: "b", Pattern: "b", Category: CategoryWarning},
+	}
+
+	tests := []struct {
+		name      string
+		overrides []Rule
+		want      []string // Enabled rule names and patterns
+	}{
+		{"no overrides", nil, []string{"a=a", "b=b"}},
+		{"replace", []Rule{{Name: "a", Pattern: "x", Category: CategoryError}}, []string{"a=x", "b=b"}},
+		{"disable", []Rule{{Name: "b", Disabled: true}}, []string{"a=a"}},
+		{"append", []Rule{{Name: "c", Pattern: "c", Category: CategoryInfo}}, []string{"a=a", "b=b", "c=c"}},
+		{"disable unknown", []Rule{{Name: "z", Disabled: true}}, []string{"a=a", "b=b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, rule := range mergeRules(base, tt.overrides) {
+				if !rule.Disabled {
+					got = append(got, rule.Name+"="+rule.Pattern)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("merged %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if base[1].Disabled {
+		t.Error("mergeRules modified the base rules")
+	}
+}
+
+func TestReadRulesFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"valid", "rules:\n  - name: db\n    pattern: deadlock\n    category: error\n    severity: critical\n", ""},
+		{"disable only", "rules:\n  - name: info-keywords\n    disabled: true\n", ""},
+		{"json", `{"rules": [{"name": "db", "pattern": "deadlock", "category": "error"}]}`, ""},
+		{"missing name", "rules:\n  - pattern: x\n    category: error\n", "name is required"},
+		{"missing pattern", "rules:\n  - name: db\n    category: error\n", "pattern is required"},
+		{"bad category", "rules:\n  - name: db\n    pattern: x\n    category: fatal\n", "category must be one of"},
+		{"bad severity", "rules:\n  - name: db\n    pattern: x\n    category: error\n    severity: huge\n", "severity must be one of"},
+		{"bad pattern", "rules:\n  - name: db\n    pattern: '('\n    category: error\n", "invalid pattern"},
+		{"bad exclude", "rules:\n  - name: db\n    pattern: x\n    category: error\n    exclude: ['[']\n", "invalid exclude pattern"},
+		{"bad ignore", "rules:\n  - name: db\n    pattern: x\n    category: error\n    ignore: ['[']\n", "invalid ignore pattern"},
+		{"duplicate", "rules:\n  - {name: db, pattern: x, category: error}\n  - {name: db, pattern: y, category: error}\n", "duplicate name"},
+		{"unknown field", "rules:\n  - name: db\n    pattern: x\n    category: error\n    level: high\n", "unknown field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rules.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := readRulesFile(path)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRuleConfigForNamespace(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	config := filepath.Join(dir, "k8s-pod-log-analyzer")
+	if err := os.MkdirAll(filepath.Join(config, "namespaces"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"rules.yaml":              "rules:\n  - {name: deadlock, pattern: deadlock, category: error}\n",
+		"namespaces/payments.yml": "rules:\n  - {name: deadlock, disabled: true}\n  - {name: declined, pattern: declined, category: warning}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(config, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rules, err := LoadRuleConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		namespace string
+		line      string
+		want      string
+	}{
+		{"default", "deadlock detected", "deadlock"},
+		{"default", "card declined", ""},
+		{"payments", "deadlock detected", ""},
+		{"payments", "card declined", "declined"},
+		{"payments", "ERROR boom", "error-keywords"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.namespace+"/"+tt.line, func(t *testing.T) {
+			got := ""
+			if rule := rules.ForNamespace(tt.namespace).Match(tt.line); rule != nil {
+				got = rule.Name
+			}
+			if got != tt.want {
+				t.Errorf("Match(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// handleSearchKey edits the search query while the prompt is open. The
+// search is applied on every keystroke.
+func (m Model) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.stopFollow()
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.searching = false
+		m.clearSearch()
+		return m, nil
+	case tea.KeyEnter:
+		m.searching = false
+		return m, nil
+	case tea.KeyBackspace:
+		if query := []rune(m.searchQuery); len(query) > 0 {
+			m.searchQuery = string(query[:len(query)-1])
+		}
+	case tea.KeyCtrlR:
+		m.searchRegex = !m.searchRegex
+	case tea.KeySpace:
+		m.searchQuery += " "
+	case tea.KeyRunes:
+		m.searchQuery += string(msg.Runes)
+	default:
+		return m, nil
+	}
+
+	m.applySearch()
+	return m, nil
+}
+
+// applySearch compiles the query, collects the matching lines and moves to
+// the first match at or below the top of the log pane. Lower-case queries
+// are case-insensitive.
+func (m *Model) applySearch() {
+	m.search, m.searchErr = nil, nil
+	if m.searchQuery != "" {
+		pattern := m.searchQuery
+		if !m.searchRegex {
+			pattern = regexp.QuoteMeta(pattern)
+		}
+		if strings.ToLower(m.searchQuery) == m.searchQuery {
+			pattern = "(?i)" + pattern
+		}
+		m.search, m.searchErr = regexp.Compile(pattern)
+	}
+
+	analysis, _ := m.shownAnalysis()
+	visible, start, _ := m.logWindow(analysis)
+	m.updateMatches()
+	if len(m.matches) == 0 {
+		return
+	}
+
+	from := 0
+	if start < len(visible) {
+		from = visible[start]
+	}
+	m.matchCursor = sort.SearchInts(m.matches, from)
+	if m.matchCursor == len(m.matches) {
+		m.matchCursor = 0
+	}
+	m.scrollToEntry(analysis, m.matches[m.matchCursor])
+}
+
+// updateMatches collects the entries of the shown analysis that match the
+// search
+func (m *Model) updateMatches() {
+	m.matches = nil
+	m.matchCursor = 0
+	if m.search == nil || len(m.pods) == 0 {
+		return
+	}
+	analysis, _ := m.shownAnalysis()
+	m.extendMatches(analysis, 0)
+}
+
+// extendMatches adds the matching entries from index from onwards, for
+// lines streamed in by the follower
+func (m *Model) extendMatches(analysis LogAnalysis, from int) {
+	if m.search == nil {
+		return
+	}
+	for i := from; i < len(analysis.Entries); i++ {
+		if m.search.MatchString(analysis.Entries[i].Raw) {
+			m.matches = append(m.matches, i)
+		}
+	}
+}
+
+// nextMatch moves to the next (delta 1) or previous (delta -1) match
+func (m *Model) nextMatch(delta int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.matchCursor = (m.matchCursor + delta + len(m.matches)) % len(m.matches)
+	analysis, _ := m.shownAnalysis()
+	m.scrollToEntry(analysis, m.matches[m.matchCursor])
+}
+
+// clearSearch removes the query, its highlights and the filter
+func (m *Model) clearSearch() {
+	m.searchQuery = ""
+	m.search = nil
+	m.searchErr = nil
+	m.searchFilter = false
+	m.matches = nil
+	m.matchCursor = 0
+}
+
+// currentMatch returns the entry index of the current match, or -1
+func (m Model) currentMatch() int {
+	if len(m.matches) == 0 {
+		return -1
+	}
+	return m.matches[m.matchCursor]
+}
+
+// highlightMatches renders text in style with every match of the search
+// highlighted
+func (m Model) highlightMatches(text string, style lipgloss.Style) string {
+	if m.search == nil {
+		return style.Render(text)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range m.search.FindAllStringIndex(text, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		if loc[0] > last {
+			b.WriteString(style.Render(text[last:loc[0]]))
+		}
+		b.WriteString(MatchStyle.Render(text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	if last < len(text) {
+		b.WriteString(style.Render(text[last:]))
+	}
+	return b.String()
+}
+
+// searchStatus describes the query and its match count for the log pane header
+func (m Model) searchStatus() string {
+	if !m.searching && m.searchQuery == "" {
+		return ""
+	}
+
+	status := "/" + m.searchQuery
+	if m.searching {
+		status += "█"
+	}
+	status = SelectedStyle.Render(status)
+	if m.searchRegex {
+		status += " " + NormalStyle.Render("("+m.localization.RegexMode+")")
+	}
+	if m.searchFilter {
+		status += " " + NormalStyle.Render("("+m.localization.FilterMode+")")
+	}
+
+	switch {
+	case m.searchErr != nil:
+		status += " " + ErrorStyle.Render(m.localization.InvalidRegex)
+	case len(m.matches) > 0:
+		status += " " + InfoStyle.Render(strconv.Itoa(m.matchCursor+1)+"/"+strconv.Itoa(len(m.matches))) + " " + m.localization.Matches
+	case m.searchQuery != "":
+		status += " " + WarningStyle.Render("0") + " " + m.localization.Matches
+	}
+	return status
+}
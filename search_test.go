@@ -0,0 +1,131 @@
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// searchModel returns a model whose log pane was searched for query
+func searchModel(query string, regex bool, lines ...string) Model {
+	m := analysisModel(lines...)
+	m, _ = update(m, keyMsg("/"))
+	if regex {
+		m, _ = update(m, tea.KeyMsg{Type: tea.KeyCtrlR})
+	}
+	if query != "" {
+		m, _ = update(m, keyMsg(query))
+	}
+	m, _ = update(m, keyMsg("enter"))
+	return m
+}
+
+func TestSearchMatches(t *testing.T) {
+	lines := []string{"GET /a.c 200", "GET /abc 200", "ERROR Timeout", "retry after timeout", "ok"}
+	tests := []struct {
+		name    string
+		query   string
+		regex   bool
+		want    []int
+		wantErr bool
+	}{
+		{"plain is literal", "a.c", false, []int{0}, false},
+		{"regex", "a.c", true, []int{0, 1}, false},
+		{"lower case ignores case", "timeout", false, []int{2, 3}, false},
+		{"upper case is case sensitive", "Timeout", false, []int{2}, false},
+		{"regex alternation", "^(ok|ERROR)", true, []int{2, 4}, false},
+		{"plain brackets", "[", false, nil, false},
+		{"invalid regex", "[", true, nil, true},
+		{"no match", "panic", false, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := searchModel(tt.query, tt.regex, lines...)
+			if (m.searchErr != nil) != tt.wantErr {
+				t.Errorf("searchErr = %v, want error %v", m.searchErr, tt.wantErr)
+			}
+			if len(m.matches) != len(tt.want) {
+				t.Fatalf("matches = %v, want %v", m.matches, tt.want)
+			}
+			for i := range tt.want {
+				if m.matches[i] != tt.want[i] {
+					t.Errorf("matches = %v, want %v", m.matches, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	previous := MatchStyle
+	defer func() { MatchStyle = previous }()
+	MatchStyle = lipgloss.NewStyle().Transform(func(s string) string { return "[" + s + "]" })
+
+	tests := []struct {
+		query string
+		regex bool
+		text  string
+		want  string
+	}{
+		{"err", false, "ERROR: err in errand", "[ERR]OR: [err] in [err]and"},
+		{"o*", true, "foo", "f[oo]"}, // Empty matches are skipped
+		{"x", false, "abc", "abc"},
+	}
+	for _, tt := range tests {
+		m := searchModel(tt.query, tt.regex, "line")
+		if got := m.highlightMatches(tt.text, lipgloss.NewStyle()); got != tt.want {
+			t.Errorf("highlightMatches(%q) for %q = %q, want %q", tt.text, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestNextMatchWraps(t *testing.T) {
+	m := searchModel("error", false, "error 1", "ok", "error 2", "ok", "error 3")
+	tests := []struct {
+		key  string
+		want int // Entry of the current match
+	}{
+		{"n", 2},
+		{"n", 4},
+		{"n", 0},
+		{"N", 4},
+		{"N", 2},
+	}
+	if got := m.currentMatch(); got != 0 {
+		t.Fatalf("first match = %d, want 0", got)
+	}
+	for i, tt := range tests {
+		m, _ = update(m, keyMsg(tt.key))
+		if got := m.currentMatch(); got != tt.want {
+			t.Errorf("after key %d (%s) match = %d, want %d", i, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestSearchFilterKeepsLineNumbers(t *testing.T) {
+	m := searchModel("error", false, "start", "error one", "ok", "ok", "error two", "end")
+	m, _ = update(m, keyMsg("F"))
+	if !m.searchFilter {
+		t.Fatal("filter mode not on")
+	}
+
+	analysis, _ := m.shownAnalysis()
+	visible := m.visibleLogEntries(analysis)
+	want := []int{2, 5}
+	if len(visible) != len(want) {
+		t.Fatalf("visible = %v, want lines %v", visible, want)
+	}
+	for i, index := range visible {
+		if line := analysis.Entries[index].Line; line != want[i] {
+			t.Errorf("filtered line %d is line %d, want %d", i, line, want[i])
+		}
+	}
+
+	m, _ = update(m, keyMsg("F"))
+	if got := len(m.visibleLogEntries(analysis)); got != 6 {
+		t.Errorf("%d lines after leaving filter mode, want 6", got)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// podFieldPrefixes start the keys of field selector requirements; any other
+// requirement of a selector typed in the TUI is a label requirement
+var podFieldPrefixes = []string{"metadata.", "spec.", "status."}
+
+// Validate checks the syntax of the label and field selectors
+func (o PodListOptions) Validate() error {
+	var errs []error
+	if _, err := labels.Parse(o.LabelSelector); err != nil {
+		errs = append(errs, fmt.Errorf("label selector %q: %w", o.LabelSelector, err))
+	}
+	if _, err := fields.ParseSelector(o.FieldSelector); err != nil {
+		errs = append(errs, fmt.Errorf("field selector %q: %w", o.FieldSelector, err))
+	}
+	return errors.Join(errs...)
+}
+
+// String returns the label and field selectors as one selector, the way
+// they are typed in the TUI
+func (o PodListOptions) String() string {
+	var parts []string
+	for _, selector := range []string{o.LabelSelector, o.FieldSelector} {
+		if selector != "" {
+			parts = append(parts, selector)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParsePodSelector splits a selector mixing label and field requirements,
+// such as "app=api,tier!=canary,status.phase!=Running", into the label and
+// field selectors of PodListOptions
+func ParsePodSelector(selector string) (PodListOptions, error) {
+	var labelReqs, fieldReqs []string
+	for _, req := range splitSelector(selector) {
+		if isFieldRequirement(req) {
+			fieldReqs = append(fieldReqs, req)
+		} else {
+			labelReqs = append(labelReqs, req)
+		}
+	}
+
+	opts := PodListOptions{
+		LabelSelector: strings.Join(labelReqs, ","),
+		FieldSelector: strings.Join(fieldReqs, ","),
+	}
+	return opts, opts.Validate()
+}
+
+// splitSelector splits a selector into its requirements at the commas that
+// are not inside a set such as "env in (prod,staging)"
+func splitSelector(selector string) []string {
+	var reqs []string
+	depth, start := 0, 0
+	add := func(req string) {
+		if req = strings.TrimSpace(req); req != "" {
+			reqs = append(reqs, req)
+		}
+	}
+	for i, r := range selector {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth = max(0, depth-1)
+		case ',':
+			if depth == 0 {
+				add(selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	add(selector[start:])
+	return reqs
+}
+
+// isFieldRequirement reports whether a requirement selects on a pod field.
+// Label keys with a prefix contain a slash, which field paths never do.
+func isFieldRequirement(req string) bool {
+	key := strings.TrimLeft(req, "!")
+	if end := strings.IndexAny(key, "!= "); end >= 0 {
+		key = key[:end]
+	}
+	if strings.Contains(key, "/") {
+		return false
+	}
+	for _, prefix := range podFieldPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleSelectorKey edits the selector prompt of the pods view
+func (m Model) handleSelectorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.stopFollow()
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.filtering = false
+		m.filterErr = nil
+	case tea.KeyEnter:
+		opts, err := ParsePodSelector(m.filterInput)
+		if err != nil {
+			m.filterErr = err
+			return m, nil
+		}
+		m.filtering = false
+		m.filterErr = nil
+		m.podOptions = opts
+		m.selectedPod = 0
+		m.onGroup = false
+		m.loading = true
+		return m, m.loadPods()
+	case tea.KeyBackspace:
+		if input := []rune(m.filterInput); len(input) > 0 {
+			m.filterInput = string(input[:len(input)-1])
+		}
+		m.filterErr = nil
+	case tea.KeySpace:
+		m.filterInput += " "
+	case tea.KeyRunes:
+		m.filterInput += string(msg.Runes)
+		m.filterErr = nil
+	}
+	return m, nil
+}
+
+// openSelectorPrompt opens the selector prompt on the current selector
+func (m *Model) openSelectorPrompt() {
+	m.filtering = true
+	m.filterInput = m.podOptions.String()
+	m.filterErr = nil
+}
+
+// selectorStatus renders the selector prompt and why the submitted selector
+// was rejected, or "" when the prompt is closed
+func (m Model) selectorStatus() string {
+	if m.filtering {
+		status := m.localization.Selector + ": " + SelectedStyle.Render(m.filterInput+"█") +
+			" " + NormalStyle.Render(m.localization.SelectorHelp)
+		if m.filterErr != nil {
+			status += "\n" + ErrorStyle.Render(m.filterErr.Error())
+		}
+		return status + "\n\n"
+	}
+	return ""
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrorSignature is a template shared by similar error lines, with the
+// variable parts masked
+type ErrorSignature struct {
+	Template  string
+	Count     int
+	FirstSeen time.Time // Zero when the lines carry no timestamp
+	LastSeen  time.Time
+	FirstLine int
+	LastLine  int
+	Example   string
+	Sources   map[string]int // Lines per source tag of merged logs
+
+	tokens []string
+}
+
+const (
+	// signatureSimilarity is the share of equal tokens a line needs to join
+	// an existing signature
+	signatureSimilarity = 0.5
+	// signatureWildcard replaces tokens that differ between lines of a signature
+	signatureWildcard = "<*>"
+	// maxTopSignatures is the number of signatures shown in the analysis view
+	maxTopSignatures = 5
+)
+
+// Variable tokens masked before clustering, applied in order
+var signatureMasks = []struct {
+	pattern *regexp.Regexp
+	mask    string
+}{
+	{regexp.MustCompile(`"[^"]*"|'[^']*'`), "<STR>"},
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), "<TS>"},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<UUID>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<IP>"},
+	{regexp.MustCompile(`(?i)\b0x[0-9a-f]+\b|\b[0-9a-f]{8,}\b`), "<HEX>"},
+	{regexp.MustCompile(`\b\d+(\.\d+)?[a-zA-Z]{0,2}\b`), "<NUM>"}, // Including units such as 300ms
+}
+
+// maskLine replaces variable tokens such as numbers, UUIDs, IP addresses,
+// hex values and quoted strings with placeholders
+func maskLine(line string) string {
+	for _, m := range signatureMasks {
+		line = m.pattern.ReplaceAllString(line, m.mask)
+	}
+	return line
+}
+
+// addSignature clusters an error line into the most similar signature of
+// the same length, or starts a new one (a simplified Drain)
+func (a *LogAnalysis) addSignature(entry LogEntry) {
+	_, message := splitTag(entry.Message)
+	tokens := strings.Fields(maskLine(message))
+	if len(tokens) == 0 {
+		return
+	}
+
+	seen := entry.Time
+	if seen.IsZero() {
+		seen = parseLogTime(strings.Fields(message)[0])
+	}
+
+	if a.signatureIndex == nil {
+		a.signatureIndex = make(map[int][]int)
+	}
+	best, bestScore := -1, 0.0
+	for _, i := range a.signatureIndex[len(tokens)] {
+		if score := tokenSimilarity(a.Signatures[i].tokens, tokens); score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	if best < 0 || bestScore < signatureSimilarity {
+		a.signatureIndex[len(tokens)] = append(a.signatureIndex[len(tokens)], len(a.Signatures))
+		a.Signatures = append(a.Signatures, ErrorSignature{
+			Template:  strings.Join(tokens, " "),
+			Count:     1,
+			FirstSeen: seen,
+			LastSeen:  seen,
+			FirstLine: entry.Line,
+			LastLine:  entry.Line,
+			Example:   entry.Raw,
+			tokens:    tokens,
+		})
+		a.Signatures[len(a.Signatures)-1].addSource(entry.Source)
+		return
+	}
+
+	sig := &a.Signatures[best]
+	merged := make([]string, len(tokens))
+	for i, token := range tokens {
+		merged[i] = sig.tokens[i]
+		if token != sig.tokens[i] {
+			merged[i] = signatureWildcard
+		}
+	}
+	sig.tokens = merged
+	sig.Template = strings.Join(merged, " ")
+	sig.Count++
+	sig.LastLine = entry.Line
+	sig.addSource(entry.Source)
+	if !seen.IsZero() {
+		if sig.FirstSeen.IsZero() || seen.Before(sig.FirstSeen) {
+			sig.FirstSeen = seen
+		}
+		if seen.After(sig.LastSeen) {
+			sig.LastSeen = seen
+		}
+	}
+}
+
+// addSource counts a line of the signature under the stream it came from
+func (sig *ErrorSignature) addSource(source string) {
+	if source == "" {
+		return
+	}
+	if sig.Sources == nil {
+		sig.Sources = make(map[string]int)
+	}
+	sig.Sources[source]++
+}
+
+// tokenSimilarity returns the share of positions where a template and a
+// line agree; wildcards match any token
+func tokenSimilarity(template, tokens []string) float64 {
+	equal := 0
+	for i, token := range tokens {
+		if template[i] == token || template[i] == signatureWildcard {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(tokens))
+}
+
+// seenAt formats when a signature was seen, falling back to the line
+// number for lines without a timestamp
+func seenAt(t time.Time, line int) string {
+	if t.IsZero() {
+		return fmt.Sprintf("#%d", line)
+	}
+	return t.Local().Format("15:04:05")
+}
+
+// TopSignatures returns the n most frequent error signatures, ties broken
+// by first occurrence
+func (a LogAnalysis) TopSignatures(n int) []ErrorSignature {
+	top := append([]ErrorSignature(nil), a.Signatures...)
+	sort.SliceStable(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].FirstLine < top[j].FirstLine
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
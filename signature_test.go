@@ -0,0 +1,101 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaskLine(t *testing.T) {
+	tests := map[string]string{
+		`user "bob" not found`:                                "user <STR> not found",
+		"at 2024-05-01T10:00:00.123Z retry":                   "at <TS> retry",
+		"request 3f2b8c1a-9d4e-4f6a-8b7c-1e2d3c4b5a69 failed": "request <UUID> failed",
+		"dial tcp 10.0.0.12:5432: connection refused":         "dial tcp <IP>: connection refused",
+		"object 0xc000123abc and deadbeefcafe":                "object <HEX> and <HEX>",
+		"took 300ms after 3 retries":                          "took <NUM> after <NUM> retries",
+		"pod api-7d9f8 restarted":                             "pod api-7d9f8 restarted",
+	}
+	for line, want := range tests {
+		if got := maskLine(line); got != want {
+			t.Errorf("maskLine(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
+
+func TestSignatures(t *testing.T) {
+	tests := []struct {
+		name  string
+		logs  []string
+		want  []string // Templates of the top signatures
+		count []int
+	}{
+		{
+			name: "variable parts are masked and merged",
+			logs: []string{
+				"ERROR user alice not found",
+				"ERROR user bob not found",
+				"ERROR user carol not found",
+			},
+			want:  []string{"ERROR user <*> not found"},
+			count: []int{3},
+		},
+		{
+			name: "different lines stay apart",
+			logs: []string{
+				"ERROR disk full",
+				"ERROR cache miss storm",
+				"ERROR cache miss storm",
+			},
+			want:  []string{"ERROR cache miss storm", "ERROR disk full"},
+			count: []int{2, 1},
+		},
+		{
+			name: "lines of different lengths never merge",
+			logs: []string{
+				"ERROR timeout after 3s",
+				"ERROR timeout after 3s on retry",
+			},
+			want:  []string{"ERROR timeout after <NUM>", "ERROR timeout after <NUM> on retry"},
+			count: []int{1, 1},
+		},
+		{
+			name: "sources are ignored when masking",
+			logs: []string{
+				"[app] ERROR payment 42 declined",
+				"[worker] ERROR payment 43 declined",
+			},
+			want:  []string{"ERROR payment <NUM> declined"},
+			count: []int{2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := AnalyzeLogs(strings.Join(tt.logs, "\n"), nil)
+			top := a.TopSignatures(maxTopSignatures)
+			if len(top) != len(tt.want) {
+				t.Fatalf("%d signatures, want %d: %+v", len(top), len(tt.want), top)
+			}
+			for i, sig := range top {
+				if sig.Template != tt.want[i] || sig.Count != tt.count[i] {
+					t.Errorf("signature %d = %q x%d, want %q x%d", i, sig.Template, sig.Count, tt.want[i], tt.count[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTopSignaturesLimit(t *testing.T) {
+	// Lines of different lengths never share a signature
+	logs := []string{"ERROR"}
+	for _, word := range []string{"alpha", "beta", "gamma", "delta", "epsilon"} {
+		logs = append(logs, logs[len(logs)-1]+" "+word)
+	}
+	top := AnalyzeLogs(strings.Join(logs, "\n"), nil).TopSignatures(maxTopSignatures)
+	if len(top) != maxTopSignatures {
+		t.Fatalf("%d signatures, want %d", len(top), maxTopSignatures)
+	}
+	if top[0].Template != "ERROR" {
+		t.Errorf("ties not broken by first occurrence: %q first", top[0].Template)
+	}
+}
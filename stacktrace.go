@@ -0,0 +1,168 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// StackTrace is a multi-line stack trace grouped into a single event
+type StackTrace struct {
+	Header   string   // The error line that started the trace
+	Line     int      // Line number of the header
+	Language string   // go, java, python, node or empty when unknown
+	Frames   []string // Every continuation line, in order
+	Causes   []string // Exception and "Caused by" lines of the chain
+
+	tag string // Container tag of the header, traces never mix containers
+}
+
+// Languages recognized in stack traces
+const (
+	TraceGo     = "go"
+	TraceJava   = "java"
+	TracePython = "python"
+	TraceNode   = "node"
+)
+
+var (
+	// Unindented lines that still belong to a stack trace
+	traceContinuationPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`^(Caused by|Suppressed): `),
+		regexp.MustCompile(`^\.\.\. \d+ (more|common frames omitted)`),
+		regexp.MustCompile(`^goroutine \d+ \[.*\]:?$`),
+		regexp.MustCompile(`^\[signal `),
+		regexp.MustCompile(`^created by `),
+		regexp.MustCompile(`^\S+\((0x[0-9a-fA-F]+|\{[^}]*\}|\.\.\.|,|\s)*\)$`),
+		regexp.MustCompile(`^Traceback \(most recent call last\):`),
+		regexp.MustCompile(`^During handling of the above exception`),
+		regexp.MustCompile(`^The above exception was the direct cause`),
+	}
+
+	// Exception lines such as java.lang.IllegalStateException: msg or ValueError: msg
+	exceptionLinePattern = regexp.MustCompile(`^([\w$]+\.)*[\w$]+(Exception|Error|Throwable|Exit|Interrupt|Fault)(: .*)?$`)
+
+	// A Python traceback starts a trace on its own even without an error line
+	pythonTracebackPattern = regexp.MustCompile(`^Traceback \(most recent call last\):`)
+)
+
+// isTraceContinuation reports whether a line continues a stack trace
+func isTraceContinuation(body string) bool {
+	if body == "" {
+		return false
+	}
+	if body[0] == ' ' || body[0] == '\t' {
+		return true
+	}
+	if exceptionLinePattern.MatchString(body) {
+		return true
+	}
+	for _, pattern := range traceContinuationPatterns {
+		if pattern.MatchString(body) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTraceStart reports whether a line starts a stack trace by itself
+func isTraceStart(body string) bool {
+	return pythonTracebackPattern.MatchString(body)
+}
+
+// detectTraceLanguage guesses the language of a stack trace line
+func detectTraceLanguage(body string) string {
+	trimmed := strings.TrimSpace(body)
+	switch {
+	case strings.HasPrefix(body, "goroutine ") || strings.Contains(trimmed, ".go:"):
+		return TraceGo
+	case strings.HasPrefix(trimmed, "File \"") || pythonTracebackPattern.MatchString(body):
+		return TracePython
+	case strings.HasPrefix(trimmed, "at ") && (strings.Contains(trimmed, ".js:") || strings.Contains(trimmed, ".ts:") ||
+		strings.Contains(trimmed, ".mjs:") || strings.Contains(trimmed, "node:")):
+		return TraceNode
+	case strings.HasPrefix(trimmed, "at ") || strings.HasPrefix(body, "Caused by: "):
+		return TraceJava
+	}
+	return ""
+}
+
+// sameTag reports whether a continuation line may belong to a trace started
+// under header tag. Untagged lines are accepted since application loggers
+// often prefix lines with "[thread] " themselves.
+func sameTag(header, tag string) bool {
+	return tag == "" || tag == header
+}
+
+// add appends a continuation line to the trace
+func (t *StackTrace) add(body string) {
+	t.Frames = append(t.Frames, body)
+	if t.Language == "" {
+		t.Language = detectTraceLanguage(body)
+	}
+	if strings.HasPrefix(body, "Caused by: ") || exceptionLinePattern.MatchString(body) {
+		t.Causes = append(t.Causes, body)
+	}
+}
+
+// close drops trailing blank lines picked up while the trace was open
+func (t *StackTrace) close() {
+	for len(t.Frames) > 0 && strings.TrimSpace(t.Frames[len(t.Frames)-1]) == "" {
+		t.Frames = t.Frames[:len(t.Frames)-1]
+	}
+}
+
+// continueTrace attaches a line to the open stack trace, or opens a new
+// trace under the preceding error or warning line. It returns false for
+// lines that are not part of a stack trace.
+func (a *LogAnalysis) continueTrace(entry *LogEntry) bool {
+	tag, body := splitTag(entry.Raw)
+	blank := strings.TrimSpace(body) == ""
+
+	if a.traceOpen {
+		trace := &a.Traces[len(a.Traces)-1]
+		if sameTag(trace.tag, tag) && (blank || isTraceContinuation(body)) {
+			trace.add(body)
+			entry.Trace = len(a.Traces)
+			return true
+		}
+		trace.close()
+		a.traceOpen = false
+	}
+
+	if blank || a.traceHeader == 0 || !isTraceContinuation(body) {
+		return false
+	}
+
+	header := &a.Entries[a.traceHeader-1]
+	headerTag, _ := splitTag(header.Raw)
+	if !sameTag(headerTag, tag) {
+		return false
+	}
+
+	a.Traces = append(a.Traces, StackTrace{Header: header.Raw, Line: header.Line, tag: tag})
+	header.Trace = len(a.Traces)
+	a.Traces[len(a.Traces)-1].add(body)
+	entry.Trace = len(a.Traces)
+	a.traceOpen = true
+	a.traceHeader = 0
+	return true
+}
+
+// noteTraceHeader remembers whether the line just classified may head a
+// stack trace. Blank lines keep the previous candidate, since Go panics
+// separate the message from the goroutine dump with one.
+func (a *LogAnalysis) noteTraceHeader(entry LogEntry) {
+	if strings.TrimSpace(entry.Raw) == "" {
+		return
+	}
+	if entry.Category == CategoryError || entry.Category == CategoryWarning {
+		a.traceHeader = len(a.Entries)
+	} else {
+		a.traceHeader = 0
+	}
+}
+
+// isTraceHeader reports whether an entry is the first line of its trace
+func (a LogAnalysis) isTraceHeader(entry LogEntry) bool {
+	return entry.Trace > 0 && a.Traces[entry.Trace-1].Line == entry.Line
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStackTraces(t *testing.T) {
+	tests := []struct {
+		name       string
+		logs       []string
+		wantHeader string // Header of the only trace, "" for no trace
+		wantLang   string
+		wantFrames int
+		wantCauses int
+		wantErrors int
+	}{
+		{
+			name: "go panic",
+			logs: []string{
+				"panic: runtime error: index out of range",
+				"",
+				"goroutine 1 [running]:",
+				"main.main()",
+				"\t/app/main.go:12 +0x1d",
+				"",
+			},
+			wantHeader: "panic: runtime error: index out of range",
+			wantLang:   TraceGo,
+			wantFrames: 3,
+			wantErrors: 1,
+		},
+		{
+			name: "java with causes",
+			logs: []string{
+				"ERROR request failed",
+				"java.lang.IllegalStateException: broken",
+				"\tat com.example.Api.handle(Api.java:42)",
+				"Caused by: java.io.IOException: closed",
+				"\tat com.example.Db.read(Db.java:7)",
+				"\t... 3 more",
+				"INFO recovered",
+			},
+			wantHeader: "ERROR request failed",
+			wantLang:   TraceJava,
+			wantFrames: 5,
+			wantCauses: 2,
+			wantErrors: 1,
+		},
+		{
+			name: "python traceback without an error line",
+			logs: []string{
+				"Traceback (most recent call last):",
+				`  File "app.py", line 3, in <module>`,
+				"    main()",
+				"ValueError: bad input",
+			},
+			wantHeader: "Traceback (most recent call last):",
+			wantLang:   TracePython,
+			wantFrames: 3,
+			wantCauses: 1,
+			wantErrors: 1,
+		},
+		{
+			name: "node",
+			logs: []string{
+				"WARN unhandled rejection",
+				"    at handler (/app/server.js:10:5)",
+				"    at node:internal/process/task_queues:95:5",
+			},
+			wantHeader: "WARN unhandled rejection",
+			wantLang:   TraceNode,
+			wantFrames: 2,
+		},
+		{
+			name: "indented lines after an info line",
+			logs: []string{
+				"INFO config loaded",
+				"  port: 8080",
+			},
+		},
+		{
+			name: "other containers do not continue a trace",
+			logs: []string{
+				"[app] ERROR crashed",
+				"[proxy]   upstream reset",
+			},
+			wantErrors: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := AnalyzeLogs(strings.Join(tt.logs, "\n"), nil)
+			if a.ErrorCount != tt.wantErrors {
+				t.Errorf("%d errors, want %d", a.ErrorCount, tt.wantErrors)
+			}
+			if tt.wantHeader == "" {
+				if len(a.Traces) != 0 {
+					t.Errorf("traces = %+v, want none", a.Traces)
+				}
+				return
+			}
+			if len(a.Traces) != 1 {
+				t.Fatalf("%d traces, want 1", len(a.Traces))
+			}
+
+			trace := a.Traces[0]
+			if trace.Header != tt.wantHeader || trace.Line != 1 {
+				t.Errorf("header = %q at line %d, want %q at line 1", trace.Header, trace.Line, tt.wantHeader)
+			}
+			if trace.Language != tt.wantLang {
+				t.Errorf("language = %q, want %q", trace.Language, tt.wantLang)
+			}
+			if len(trace.Frames) != tt.wantFrames {
+				t.Errorf("frames = %q, want %d", trace.Frames, tt.wantFrames)
+			}
+			if len(trace.Causes) != tt.wantCauses {
+				t.Errorf("causes = %q, want %d", trace.Causes, tt.wantCauses)
+			}
+			if !a.isTraceHeader(a.Entries[0]) {
+				t.Error("first entry is not the trace header")
+			}
+		})
+	}
+}
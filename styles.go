@@ -39,6 +39,12 @@ var (
 			Foreground(lipgloss.Color("#28CA42")).
 			Bold(true)
 
+	// Search match highlight
+	MatchStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#1a1a2e")).
+			Background(lipgloss.Color("#FFBD2E")).
+			Bold(true)
+
 	// Pod status colors
 	RunningStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#28CA42")).
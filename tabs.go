@@ -0,0 +1,128 @@
+package main
+
+import "strings"
+
+// Tabs of the log pane
+const (
+	TabAll = iota
+	TabErrors
+	TabWarnings
+	TabInfo
+	TabUnclassified
+	tabCount
+)
+
+// defaultContextLines is how many lines are shown before and after a line
+// opened in context
+const defaultContextLines = 5
+
+// tabEntries returns the indexes of the entries listed in a tab. Category
+// tabs list exactly the lines the analyzer counted in that category; stack
+// trace frames belong to their header and blank lines are skipped.
+func (a LogAnalysis) tabEntries(tab int) []int {
+	var category string
+	switch tab {
+	case TabErrors:
+		category = CategoryError
+	case TabWarnings:
+		category = CategoryWarning
+	case TabInfo:
+		category = CategoryInfo
+	}
+
+	var entries []int
+	for i, entry := range a.Entries {
+		if entry.Category != category {
+			continue
+		}
+		if category == "" && (entry.Trace > 0 || strings.TrimSpace(entry.Raw) == "") {
+			continue
+		}
+		entries = append(entries, i)
+	}
+	return entries
+}
+
+// tabLabel returns the localized name of a tab
+func (m Model) tabLabel(tab int) string {
+	switch tab {
+	case TabErrors:
+		return m.localization.Errors
+	case TabWarnings:
+		return m.localization.Warnings
+	case TabInfo:
+		return m.localization.TabInfo
+	case TabUnclassified:
+		return m.localization.TabUnclassified
+	}
+	return m.localization.TabAll
+}
+
+// setTab switches the log pane to a tab and closes the context view
+func (m *Model) setTab(tab int) {
+	m.logTab = (tab + tabCount) % tabCount
+	m.tabCursor = 0
+	m.contextLine = 0
+}
+
+// contextLines returns how many lines surround a line opened in context
+func (m Model) contextLines() int {
+	if m.contextSize > 0 {
+		return m.contextSize
+	}
+	return defaultContextLines
+}
+
+// logPane returns the entries of the log pane, the range of them on screen
+// and the entry under the cursor, or -1. The context view shows the lines
+// around the opened line, category tabs a list centered on their cursor and
+// the All tab the raw log scrolled from the tail.
+func (m Model) logPane(analysis LogAnalysis) ([]int, int, int, int) {
+	height := m.logPaneHeight()
+
+	if m.contextLine > 0 {
+		center := m.contextLine - 1
+		from := max(0, center-m.contextLines())
+		to := min(len(analysis.Entries), center+m.contextLines()+1)
+		entries := make([]int, 0, to-from)
+		for i := from; i < to; i++ {
+			entries = append(entries, i)
+		}
+		start := max(0, min(center-from-height/2, len(entries)-height))
+		return entries, start, min(len(entries), start+height), center
+	}
+
+	if m.logTab != TabAll {
+		entries := analysis.tabEntries(m.logTab)
+		if len(entries) == 0 {
+			return nil, 0, 0, -1
+		}
+		cursor := min(m.tabCursor, len(entries)-1)
+		start := max(0, min(cursor-height/2, len(entries)-height))
+		return entries, start, min(len(entries), start+height), entries[cursor]
+	}
+
+	visible, start, end := m.logWindow(analysis)
+	return visible, start, end, m.currentMatch()
+}
+
+// moveLogCursor moves the context line or the category tab cursor
+func (m *Model) moveLogCursor(delta int) {
+	analysis, _ := m.shownAnalysis()
+	if m.contextLine > 0 {
+		m.contextLine = max(1, min(len(analysis.Entries), m.contextLine+delta))
+		return
+	}
+	entries := analysis.tabEntries(m.logTab)
+	m.tabCursor = max(0, min(len(entries)-1, m.tabCursor+delta))
+}
+
+// openContext shows the line under the tab cursor with its surrounding lines
+func (m *Model) openContext() {
+	analysis, _ := m.shownAnalysis()
+	entries := analysis.tabEntries(m.logTab)
+	if len(entries) == 0 {
+		return
+	}
+	m.contextLine = entries[min(m.tabCursor, len(entries)-1)] + 1
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTabEntries(t *testing.T) {
+	analysis := AnalyzeLogs("INFO started\n"+
+		"ERROR request failed\n"+
+		"java.lang.IllegalStateException: broken\n"+
+		"\tat com.example.Api.handle(Api.java:42)\n"+
+		"WARN disk almost full\n"+
+		"plain line\n"+
+		"\n"+
+		"ERROR second failure\n", nil)
+
+	tests := []struct {
+		tab  int
+		want []int
+	}{
+		{TabErrors, []int{1, 7}}, // Without the frames of the trace
+		{TabWarnings, []int{4}},
+		{TabInfo, []int{0}},
+		{TabUnclassified, []int{5}}, // Without the frames and the blank line
+	}
+	for _, tt := range tests {
+		got := analysis.tabEntries(tt.tab)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("tab %d entries = %v, want %v", tt.tab, got, tt.want)
+		}
+	}
+	if len(analysis.tabEntries(TabErrors)) != analysis.ErrorCount {
+		t.Errorf("errors tab does not list the %d counted errors", analysis.ErrorCount)
+	}
+}
+
+func TestLogPaneContext(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	m := analysisModel(lines...)
+	m.contextSize = 3
+	analysis, _ := m.shownAnalysis()
+
+	tests := []struct {
+		name        string
+		contextLine int
+		wantFirst   int
+		wantLast    int
+	}{
+		{"first line", 1, 0, 3},
+		{"middle", 10, 6, 12},
+		{"last line", 20, 16, 19},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.contextLine = tt.contextLine
+			entries, start, end, cursor := m.logPane(analysis)
+			if entries[0] != tt.wantFirst || entries[len(entries)-1] != tt.wantLast {
+				t.Errorf("entries %d-%d, want %d-%d", entries[0], entries[len(entries)-1], tt.wantFirst, tt.wantLast)
+			}
+			if start != 0 || end != len(entries) {
+				t.Errorf("on screen %d-%d of %d entries", start, end, len(entries))
+			}
+			if cursor != tt.contextLine-1 {
+				t.Errorf("cursor = %d, want %d", cursor, tt.contextLine-1)
+			}
+		})
+	}
+}
+
+func TestLogPaneTabCursor(t *testing.T) {
+	m := analysisModel("ERROR one", "ok", "ERROR two")
+	analysis, _ := m.shownAnalysis()
+
+	m.setTab(TabErrors)
+	m.tabCursor = 99
+	if _, _, _, cursor := m.logPane(analysis); cursor != 2 {
+		t.Errorf("cursor past the end = entry %d, want the last error 2", cursor)
+	}
+
+	m.setTab(TabWarnings)
+	if entries, _, _, cursor := m.logPane(analysis); entries != nil || cursor != -1 {
+		t.Errorf("empty tab = %v with cursor %d", entries, cursor)
+	}
+
+	// Opening a line in context and switching tab closes it again
+	m.setTab(TabErrors)
+	m.tabCursor = 1
+	m.openContext()
+	if m.contextLine != 3 {
+		t.Errorf("context line = %d, want 3", m.contextLine)
+	}
+	m.setTab(TabAll)
+	if m.contextLine != 0 {
+		t.Error("context view still open after switching tab")
+	}
+}
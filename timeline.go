@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sparkBlocks are the bar heights of the timeline histogram, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// maxTimelineBuckets caps the columns of the timeline histogram
+const maxTimelineBuckets = 120
+
+// timelineLabelWidth is the width of the row labels of the timeline
+const timelineLabelWidth = 12
+
+// timeBucket counts the lines logged during one column of the timeline
+type timeBucket struct {
+	start    time.Time
+	lines    int
+	errors   int
+	warnings int
+	first    int // Index of the first entry logged at or after start, -1 when none
+}
+
+// entryTimes returns when every entry was logged. Lines without a time
+// share the time of the line before them; ok is false when no line has one.
+func (a LogAnalysis) entryTimes() ([]time.Time, bool) {
+	times := make([]time.Time, len(a.Entries))
+	var last time.Time
+	for i, entry := range a.Entries {
+		if !entry.Time.IsZero() {
+			last = entry.Time
+		}
+		times[i] = last
+	}
+	return times, !last.IsZero()
+}
+
+// timeline buckets the entries of an analysis into n columns from the
+// given time on, each step long. Entries outside the range are counted in
+// the first or last column.
+func (a LogAnalysis) timeline(times []time.Time, from time.Time, step time.Duration, n int) []timeBucket {
+	buckets := make([]timeBucket, n)
+	for i := range buckets {
+		buckets[i].start = from.Add(time.Duration(i) * step)
+		buckets[i].first = -1
+	}
+
+	for i, entry := range a.Entries {
+		column := 0
+		if !times[i].IsZero() {
+			column = max(0, min(n-1, int(times[i].Sub(from)/step)))
+		}
+		bucket := &buckets[column]
+		bucket.lines++
+		switch entry.Category {
+		case CategoryError:
+			bucket.errors++
+		case CategoryWarning:
+			bucket.warnings++
+		}
+		if bucket.first < 0 {
+			bucket.first = i
+		}
+	}
+
+	// An empty column leads to the next line logged after it
+	for i := n - 2; i >= 0; i-- {
+		if buckets[i].first < 0 {
+			buckets[i].first = buckets[i+1].first
+		}
+	}
+	return buckets
+}
+
+// timelineBuckets returns the columns of the timeline of the shown analysis
+// and how long each one is. The timeline covers the analyzed time range of
+// the current instance, or else the time span of the lines. It is empty
+// when no line has a time.
+func (m Model) timelineBuckets(analysis LogAnalysis) ([]timeBucket, time.Duration) {
+	times, ok := analysis.entryTimes()
+	if !ok {
+		return nil, 0
+	}
+
+	var from, to time.Time
+	for _, at := range times {
+		if at.IsZero() {
+			continue
+		}
+		if from.IsZero() || at.Before(from) {
+			from = at
+		}
+		if at.After(to) {
+			to = at
+		}
+	}
+	if start := m.window.start(analysis.AnalyzedAt); !start.IsZero() && !m.showPrevious {
+		if start.Before(from) {
+			from = start
+		}
+		if end := m.window.end(analysis.AnalyzedAt); end.After(to) {
+			to = end
+		}
+	}
+
+	n := max(10, min(maxTimelineBuckets, m.width-timelineLabelWidth-10))
+	// Round the step up so the last line falls in the last column
+	span := to.Sub(from)
+	if span < time.Second {
+		span = time.Second
+	}
+	step := (span + time.Duration(n) - 1) / time.Duration(n)
+	return analysis.timeline(times, from, step, n), step
+}
+
+// roundStep rounds the length of a timeline column for display
+func roundStep(step time.Duration) time.Duration {
+	if step < time.Second {
+		return step.Round(time.Millisecond)
+	}
+	return step.Round(time.Second)
+}
+
+// sparkline renders counts as bars scaled to the largest one
+func sparkline(counts []int) string {
+	peak := 0
+	for _, count := range counts {
+		peak = max(peak, count)
+	}
+
+	var b strings.Builder
+	for _, count := range counts {
+		if count == 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		b.WriteRune(sparkBlocks[(count*len(sparkBlocks)-1)/peak])
+	}
+	return b.String()
+}
+
+// timelineTime formats a time of the timeline, with the date when the
+// timeline spans more than a day
+func timelineTime(at time.Time, step time.Duration, n int) string {
+	if step*time.Duration(n) > 24*time.Hour {
+		return at.Local().Format("01-02 15:04")
+	}
+	return at.Local().Format("15:04:05")
+}
+
+// renderTimeline renders histograms of the lines, errors and warnings of
+// the shown analysis over time, with the selected column and its counts
+func (m Model) renderTimeline(analysis LogAnalysis) string {
+	buckets, step := m.timelineBuckets(analysis)
+	if len(buckets) == 0 {
+		return ""
+	}
+	n := len(buckets)
+
+	var b strings.Builder
+	end := buckets[n-1].start.Add(step)
+	b.WriteString(fmt.Sprintf("%s: %s - %s (%s/%s)\n", m.localization.Timeline,
+		timelineTime(buckets[0].start, step, n), timelineTime(end, step, n),
+		roundStep(step), m.localization.Column))
+
+	rows := []struct {
+		label string
+		count func(timeBucket) int
+		style func(...string) string
+	}{
+		{m.localization.Lines, func(b timeBucket) int { return b.lines }, InfoStyle.Render},
+		{m.localization.Errors, func(b timeBucket) int { return b.errors }, ErrorStyle.Render},
+		{m.localization.Warnings, func(b timeBucket) int { return b.warnings }, WarningStyle.Render},
+	}
+	for i, row := range rows {
+		counts := make([]int, n)
+		total := 0
+		for j, bucket := range buckets {
+			counts[j] = row.count(bucket)
+			total += counts[j]
+		}
+		// Rows without any line are left out, except the line counts
+		if total == 0 && i > 0 {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("  %-*s%s\n", timelineLabelWidth-2, row.label, row.style(sparkline(counts))))
+	}
+
+	if m.timeCursor > 0 {
+		column := min(m.timeCursor, n) - 1
+		bucket := buckets[column]
+		details := fmt.Sprintf("%s - %s: %d %s, %d %s, %d %s",
+			timelineTime(bucket.start, step, n), timelineTime(bucket.start.Add(step), step, n),
+			bucket.lines, strings.ToLower(m.localization.Lines),
+			bucket.errors, strings.ToLower(m.localization.Errors),
+			bucket.warnings, strings.ToLower(m.localization.Warnings))
+		b.WriteString(strings.Repeat(" ", timelineLabelWidth+column) + SelectedStyle.Render("▲") + "\n")
+		b.WriteString(strings.Repeat(" ", timelineLabelWidth) + SelectedStyle.Render(details) + "\n")
+	}
+	return b.String()
+}
+
+// moveTimeCursor selects the previous or next column of the timeline, the
+// last or first one when none is selected, and scrolls the log pane to the
+// first line logged in it
+func (m *Model) moveTimeCursor(delta int) {
+	analysis, ok := m.shownAnalysis()
+	if !ok {
+		return
+	}
+	buckets, _ := m.timelineBuckets(analysis)
+	if len(buckets) == 0 {
+		return
+	}
+
+	switch {
+	case m.timeCursor == 0 && delta < 0:
+		m.timeCursor = len(buckets)
+	case m.timeCursor == 0:
+		m.timeCursor = 1
+	default:
+		m.timeCursor = max(1, min(len(buckets), m.timeCursor+delta))
+	}
+	if first := buckets[m.timeCursor-1].first; first >= 0 {
+		m.scrollToEntry(analysis, first)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEntryTimes(t *testing.T) {
+	at := func(second int) time.Time { return time.Date(2024, 5, 1, 10, 0, second, 0, time.UTC) }
+	analysis := LogAnalysis{Entries: []LogEntry{
+		{}, // Before any time
+		{Time: at(1)},
+		{}, // Inherits 1
+		{}, // Inherits 1
+		{Time: at(5)},
+		{}, // Inherits 5
+	}}
+
+	times, ok := analysis.entryTimes()
+	want := []time.Time{{}, at(1), at(1), at(1), at(5), at(5)}
+	if !ok {
+		t.Fatal("no time found")
+	}
+	for i := range want {
+		if !times[i].Equal(want[i]) {
+			t.Errorf("entry %d at %v, want %v", i, times[i], want[i])
+		}
+	}
+
+	if _, ok := (LogAnalysis{Entries: []LogEntry{{}, {}}}).entryTimes(); ok {
+		t.Error("entries without a time have one")
+	}
+}
+
+func TestTimelineBuckets(t *testing.T) {
+	from := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	entries := []struct {
+		offset   time.Duration
+		category string
+	}{
+		{-time.Minute, CategoryInfo},               // Before the range: first column
+		{0, CategoryError},                         // Column 0
+		{1500 * time.Millisecond, CategoryWarning}, // Column 1
+		{1900 * time.Millisecond, CategoryError},   // Column 1
+		{4 * time.Second, ""},                      // Column 4
+		{time.Hour, CategoryError},                 // After the range: last column
+	}
+	var analysis LogAnalysis
+	var times []time.Time
+	for _, e := range entries {
+		analysis.Entries = append(analysis.Entries, LogEntry{Category: e.category})
+		times = append(times, from.Add(e.offset))
+	}
+
+	buckets := analysis.timeline(times, from, time.Second, 6)
+	want := []struct{ lines, errors, warnings, first int }{
+		{2, 1, 0, 0},
+		{2, 1, 1, 2},
+		{0, 0, 0, 4}, // Empty columns lead to the next line
+		{0, 0, 0, 4},
+		{1, 0, 0, 4},
+		{1, 1, 0, 5},
+	}
+	for i, w := range want {
+		b := buckets[i]
+		if b.lines != w.lines || b.errors != w.errors || b.warnings != w.warnings || b.first != w.first {
+			t.Errorf("column %d = %d lines, %d errors, %d warnings, first %d, want %+v",
+				i, b.lines, b.errors, b.warnings, b.first, w)
+		}
+		if start := from.Add(time.Duration(i) * time.Second); !b.start.Equal(start) {
+			t.Errorf("column %d starts at %v, want %v", i, b.start, start)
+		}
+	}
+}
+
+func TestMoveTimeCursor(t *testing.T) {
+	// Three timestamped lines 49s apart with untimed lines between them, so
+	// the 98 columns of a 120 wide timeline are a second each
+	var lines []string
+	for i, second := range []int{0, 49, 98} {
+		if i > 0 {
+			lines = append(lines, strings.TrimSuffix(strings.Repeat("filler\n", 40), "\n"))
+		}
+		at := time.Date(2024, 5, 1, 10, second/60, second%60, 0, time.UTC)
+		lines = append(lines, fmt.Sprintf("time=%s level=error msg=line%d", at.Format(time.RFC3339), i))
+	}
+
+	tests := []struct {
+		name       string
+		moves      []int
+		wantCursor int
+		wantEntry  int // Entry scrolled to
+	}{
+		{"first column", []int{1}, 1, 0},
+		{"empty column leads to the next line", []int{1, 1}, 2, 41},
+		{"last column when none is selected", []int{-1}, 98, 82},
+		{"clamped to the first column", []int{1, -1, -1}, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := analysisModel(lines...)
+			m.window = timeRange{}
+			for _, delta := range tt.moves {
+				m.moveTimeCursor(delta)
+			}
+			if m.timeCursor != tt.wantCursor {
+				t.Errorf("cursor = %d, want %d", m.timeCursor, tt.wantCursor)
+			}
+
+			analysis, _ := m.shownAnalysis()
+			visible, start, end := m.logWindow(analysis)
+			if tt.wantEntry < visible[start] || tt.wantEntry > visible[end-1] {
+				t.Errorf("entries %d-%d on screen, want %d among them", visible[start], visible[end-1], tt.wantEntry)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultSince is the window analyzed when neither --since nor --since-time
+// is given
+const defaultSince = 5 * time.Minute
+
+// timePresets are the windows offered by the time window picker, which
+// offers a custom range after them
+var timePresets = []time.Duration{5 * time.Minute, 15 * time.Minute, time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+// timeRange is the window of the analyzed logs: the last since, or from an
+// absolute time on, up to until. The API has no end time, so the lines
+// logged after until are dropped once fetched.
+type timeRange struct {
+	since time.Duration // Used when from is zero, 0 for the whole log
+	from  time.Time
+	until time.Time // Zero for now
+}
+
+// logOptions returns the options requesting the lines of the range
+func (r timeRange) logOptions() LogOptions {
+	if !r.from.IsZero() {
+		return LogOptions{SinceTime: r.from, Until: r.until}
+	}
+	return LogOptions{Since: r.since, Until: r.until}
+}
+
+// start returns when the range starts, a relative one counted back from
+// now, or zero for the whole log
+func (r timeRange) start(now time.Time) time.Time {
+	if !r.from.IsZero() {
+		return r.from
+	}
+	if r.since > 0 {
+		return now.Add(-r.since)
+	}
+	return time.Time{}
+}
+
+// end returns when the range ends, now when it is open
+func (r timeRange) end(now time.Time) time.Time {
+	if !r.until.IsZero() {
+		return r.until
+	}
+	return now
+}
+
+// closed reports whether the range has an end, so no new line can show up
+// in it
+func (r timeRange) closed() bool {
+	return !r.until.IsZero()
+}
+
+// String returns the range the way it is typed in the time window picker
+func (r timeRange) String() string {
+	start := shortDuration(r.since)
+	if !r.from.IsZero() {
+		start = r.from.Format(time.RFC3339)
+	}
+	if r.until.IsZero() {
+		return start
+	}
+	return start + ".." + r.until.Format(time.RFC3339)
+}
+
+// shortDuration formats a duration without its trailing zero units, such as
+// 1h rather than 1h0m0s
+func shortDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// newTimeRange validates the --since, --since-time and --until values, ""
+// when not given. Times are RFC3339, or durations counted back from now.
+func newTimeRange(since, sinceTime, until string, now time.Time) (timeRange, error) {
+	r := timeRange{since: defaultSince}
+	var err error
+	switch {
+	case since != "" && sinceTime != "":
+		return timeRange{}, errors.New("--since and --since-time cannot be used together")
+	case since != "":
+		if r.since, err = parseSince(since); err != nil {
+			return timeRange{}, fmt.Errorf("--since: %w", err)
+		}
+	case sinceTime != "":
+		if r.from, err = parseTimePoint(sinceTime, now); err != nil {
+			return timeRange{}, fmt.Errorf("--since-time: %w", err)
+		}
+	}
+	if until != "" {
+		if r.until, err = parseTimePoint(until, now); err != nil {
+			return timeRange{}, fmt.Errorf("--until: %w", err)
+		}
+	}
+	return r, r.validate(now)
+}
+
+// parseCustomRange parses a range typed in the time window picker: a
+// duration such as 2h for the last two hours, or a start and an optional
+// end separated by "..", each an RFC3339 time or a duration ago
+func parseCustomRange(input string, now time.Time) (timeRange, error) {
+	start, end, _ := strings.Cut(strings.TrimSpace(input), "..")
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	if start == "" {
+		return timeRange{}, errors.New("the time range has no start")
+	}
+
+	if end == "" {
+		if since, err := parseSince(start); err == nil {
+			return timeRange{since: since}, nil
+		}
+	}
+
+	var r timeRange
+	var err error
+	if r.from, err = parseTimePoint(start, now); err != nil {
+		return timeRange{}, fmt.Errorf("start: %w", err)
+	}
+	if end != "" {
+		if r.until, err = parseTimePoint(end, now); err != nil {
+			return timeRange{}, fmt.Errorf("end: %w", err)
+		}
+	}
+	return r, r.validate(now)
+}
+
+// parseSince parses a relative start such as 5m or 2h30m
+func parseSince(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%q is not a duration such as 5m or 2h30m", value)
+	}
+	return d, nil
+}
+
+// parseTimePoint parses an RFC3339 time, or a duration such as 2h or -2h
+// counted back from now
+func parseTimePoint(value string, now time.Time) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+	if d, err := time.ParseDuration(strings.TrimPrefix(value, "-")); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%q is neither an RFC3339 time such as 2024-05-01T10:00:00Z nor a duration ago such as 2h", value)
+}
+
+// validate checks that the range starts in the past and ends after it
+// starts
+func (r timeRange) validate(now time.Time) error {
+	start := r.start(now)
+	if start.After(now) {
+		return fmt.Errorf("the time range starts in the future, at %s", start.Format(time.RFC3339))
+	}
+	if r.closed() && !r.until.After(start) {
+		return fmt.Errorf("the time range ends at %s, not after its start at %s",
+			r.until.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// handleTimeKey edits the time window picker: the arrows select a preset or
+// the custom range, which is typed in place
+func (m Model) handleTimeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	custom := len(timePresets)
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.stopFollow()
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.picking = false
+		m.pickErr = nil
+	case tea.KeyLeft:
+		m.pickCursor = max(0, m.pickCursor-1)
+		m.pickErr = nil
+	case tea.KeyRight:
+		m.pickCursor = min(custom, m.pickCursor+1)
+		m.pickErr = nil
+	case tea.KeyEnter:
+		var r timeRange
+		if m.pickCursor < custom {
+			r = timeRange{since: timePresets[m.pickCursor]}
+		} else {
+			var err error
+			if r, err = parseCustomRange(m.pickInput, time.Now()); err != nil {
+				m.pickErr = err
+				return m, nil
+			}
+		}
+		if m.pickBase {
+			return m, m.setBaseRange(r)
+		}
+		return m, m.setTimeRange(r)
+	case tea.KeyBackspace:
+		if input := []rune(m.pickInput); len(input) > 0 {
+			m.pickInput = string(input[:len(input)-1])
+		}
+		m.pickCursor = custom
+		m.pickErr = nil
+	case tea.KeyRunes:
+		m.pickInput += string(msg.Runes)
+		m.pickCursor = custom
+		m.pickErr = nil
+	}
+	return m, nil
+}
+
+// openTimePicker opens the time window picker on the current range, or on
+// the range of the diff's base side when base is set
+func (m *Model) openTimePicker(current timeRange, base bool) {
+	m.picking = true
+	m.pickBase = base
+	m.pickErr = nil
+	m.pickInput = ""
+	m.pickCursor = len(timePresets)
+	for i, preset := range timePresets {
+		if current == (timeRange{since: preset}) {
+			m.pickCursor = i
+		}
+	}
+	if m.pickCursor == len(timePresets) {
+		m.pickInput = current.String()
+	}
+}
+
+// setTimeRange analyzes another time range and loads the current view
+// again with it
+func (m *Model) setTimeRange(r timeRange) tea.Cmd {
+	m.picking = false
+	m.pickErr = nil
+	m.window = r
+	// The error counts of the pod grid were for the previous range
+	m.podErrors = nil
+	m.loading = true
+	switch m.currentView {
+	case "pods":
+		return m.loadPods()
+	case "analysis":
+		m.stopFollow()
+		m.timeCursor = 0
+		return m.loadLogs()
+	case "diff":
+		return m.loadDiff(m.diff.base, m.diff.other)
+	}
+	m.loading = false
+	return nil
+}
+
+// timeStatus renders the time window picker and why the custom range was
+// rejected, or "" when the picker is closed
+func (m Model) timeStatus() string {
+	if !m.picking {
+		return ""
+	}
+
+	options := make([]string, 0, len(timePresets)+1)
+	for _, preset := range timePresets {
+		options = append(options, shortDuration(preset))
+	}
+	custom := m.localization.Custom
+	if m.pickCursor == len(timePresets) {
+		custom += ": " + m.pickInput + "█"
+	}
+	options = append(options, custom)
+	for i, option := range options {
+		if i == m.pickCursor {
+			options[i] = SelectedStyle.Render("[" + option + "]")
+		} else {
+			options[i] = NormalStyle.Render(option)
+		}
+	}
+
+	label := m.localization.TimeWindow
+	if m.pickBase {
+		label = m.localization.BaseTimeWindow
+	}
+	status := label + ": " + strings.Join(options, " ") +
+		"\n" + NormalStyle.Render(m.localization.TimePickerHelp)
+	if m.pickErr != nil {
+		status += "\n" + ErrorStyle.Render(m.pickErr.Error())
+	}
+	return status + "\n\n"
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewTimeRange(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name                    string
+		since, sinceTime, until string
+		want                    timeRange
+		wantErr                 string
+	}{
+		{name: "default", want: timeRange{since: defaultSince}},
+		{name: "since", since: "2h30m", want: timeRange{since: 150 * time.Minute}},
+		{name: "whole log", since: "0", want: timeRange{}},
+		{name: "since time", sinceTime: "2024-05-01T10:00:00Z",
+			want: timeRange{since: defaultSince, from: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}},
+		{name: "since time ago", sinceTime: "3h",
+			want: timeRange{since: defaultSince, from: now.Add(-3 * time.Hour)}},
+		{name: "until", since: "1h", until: "-15m",
+			want: timeRange{since: time.Hour, until: now.Add(-15 * time.Minute)}},
+		{name: "both starts", since: "1h", sinceTime: "2h", wantErr: "cannot be used together"},
+		{name: "negative since", since: "-1h", wantErr: "--since:"},
+		{name: "bad since time", sinceTime: "yesterday", wantErr: "--since-time:"},
+		{name: "bad until", until: "10:00", wantErr: "--until:"},
+		{name: "future start", sinceTime: "2024-05-02T00:00:00Z", wantErr: "starts in the future"},
+		{name: "ends before it starts", since: "1h", until: "2h", wantErr: "not after its start"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newTimeRange(tt.since, tt.sinceTime, tt.until, now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("range = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCustomRange(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input   string
+		want    timeRange
+		wantErr string
+	}{
+		{input: "2h", want: timeRange{since: 2 * time.Hour}},
+		{input: " 45m ", want: timeRange{since: 45 * time.Minute}},
+		{input: "2h..1h", want: timeRange{from: now.Add(-2 * time.Hour), until: now.Add(-time.Hour)}},
+		{input: "2024-05-01T09:00:00Z..2024-05-01T10:30:00Z",
+			want: timeRange{from: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC), until: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)}},
+		{input: "2024-05-01T09:00:00Z", want: timeRange{from: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)}},
+		{input: "3h..", want: timeRange{since: 3 * time.Hour}},
+		{input: "", wantErr: "no start"},
+		{input: "..1h", wantErr: "no start"},
+		{input: "soon", wantErr: "start:"},
+		{input: "2h..later", wantErr: "end:"},
+		{input: "1h..2h", wantErr: "not after its start"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseCustomRange(tt.input, now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("range = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeRangeString(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]timeRange{
+		"5m":                   {since: 5 * time.Minute},
+		"1h":                   {since: time.Hour},
+		"2h30m":                {since: 150 * time.Minute},
+		"2024-05-01T09:00:00Z": {from: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)},
+		"2024-05-01T09:00:00Z..2024-05-01T10:00:00Z": {
+			from: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC), until: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	for want, r := range tests {
+		if got := r.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+		// The picker offers the current range for editing
+		if parsed, err := parseCustomRange(r.String(), now); err != nil || parsed != r {
+			t.Errorf("parseCustomRange(%q) = %+v, %v, want %+v", r.String(), parsed, err, r)
+		}
+	}
+}
+
+func TestTrimUntil(t *testing.T) {
+	until := time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		logs string
+		want string
+	}{
+		{"empty", "", ""},
+		{"all before",
+			"2024-05-01T10:00:00Z a\n2024-05-01T10:59:59.999Z b\n",
+			"2024-05-01T10:00:00Z a\n2024-05-01T10:59:59.999Z b\n"},
+		{"cut at until with the continuation lines after it",
+			"2024-05-01T10:00:00Z a\n\tat frame\n2024-05-01T11:00:00Z b\n\tat frame\n2024-05-01T10:30:00Z late\n",
+			"2024-05-01T10:00:00Z a\n\tat frame\n"},
+		{"all after", "2024-05-01T12:00:00Z a\n", ""},
+		{"no trailing newline", "2024-05-01T10:00:00Z a\n2024-05-01T11:30:00Z b", "2024-05-01T10:00:00Z a\n"},
+		{"leading lines without timestamps kept", "\tat frame\n2024-05-01T10:00:00Z a", "\tat frame\n2024-05-01T10:00:00Z a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trimUntil(tt.logs, until); got != tt.want {
+				t.Errorf("trimUntil = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchLogsUntil(t *testing.T) {
+	f := NewFakeClient()
+	f.SetLogs("ns", "api", "app", false, "2024-05-01T10:00:00Z a\n2024-05-01T11:00:00Z b\n\tat frame\n")
+	until := time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		opts LogOptions
+		want string
+	}{
+		{"trimmed with timestamps", LogOptions{Container: "app", Timestamps: true, Until: until},
+			"2024-05-01T10:00:00Z a\n"},
+		{"not trimmed without them", LogOptions{Container: "app", Until: until}, "a\nb\n\tat frame\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fetchLogs(context.Background(), f, "ns", "api", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("logs = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
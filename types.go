@@ -1,6 +1,7 @@
 package main
 
 import (
+	"regexp"
 	"time"
 )
 
@@ -8,10 +9,69 @@ import (
 type PodInfo struct {
 	Name       string
 	Status     string
+	Reason     string // Message explaining the status, "" when there is none
 	Ready      string
 	Age        string
 	Restarts   string
 	StatusIcon string
+	Containers []ContainerInfo
+	Context    string // Kubeconfig context of the pod in fan-out mode
+	Created    time.Time
+	Workload   string // Owning workload as Kind/name, "" for standalone pods
+}
+
+// Container types as shown in the container picker
+const (
+	ContainerTypeRegular   = "regular"
+	ContainerTypeInit      = "init"
+	ContainerTypeSidecar   = "sidecar"
+	ContainerTypeEphemeral = "ephemeral"
+)
+
+// AllContainers selects the interleaved logs of every container of a pod
+const AllContainers = "*"
+
+// ContainerInfo holds the state of a single container of a pod
+type ContainerInfo struct {
+	Name     string
+	Type     string
+	State    string
+	Ready    bool
+	Restarts int
+	Started  bool // Whether the container has ever produced logs
+
+	LastTermination *ContainerTermination
+}
+
+// ContainerTermination describes how the previous instance of a container ended
+type ContainerTermination struct {
+	Reason     string
+	ExitCode   int32
+	Signal     int32
+	FinishedAt time.Time
+}
+
+// logTarget identifies the log stream being analyzed
+type logTarget struct {
+	context    string // Kubeconfig context in fan-out mode
+	pod        string
+	container  string      // Container name or AllContainers
+	containers []string    // Containers whose logs are fetched
+	restarted  []string    // Containers with a previous instance
+	workload   string      // Workload as Kind/name when all its replicas are analyzed
+	replicas   []logTarget // Targets of the workload's pods
+}
+
+// key returns the logs map key for the target
+func (t logTarget) key() string {
+	name := t.pod
+	if t.workload != "" {
+		name = t.workload
+	}
+	if t.context != "" {
+		return t.context + "/" + name + "/" + t.container
+	}
+	return name + "/" + t.container
 }
 
 // LogAnalysis holds the analysis results for a pod
@@ -23,28 +83,100 @@ type LogAnalysis struct {
 	Errors       []string
 	Warnings     []string
 	Info         []string
-	RawLogs      string
+	RuleHits     map[string]int   // Matched lines per rule name
+	FormatCounts map[string]int   // Lines per detected log format
+	Entries      []LogEntry       // Parsed form of every line
+	Traces       []StackTrace     // Multi-line stack traces, each counted once
+	Signatures   []ErrorSignature // Error lines clustered into templates
 	AnalyzedAt   time.Time
+	LastLogged   time.Time // API timestamp of the newest line, zero when none had one
+
+	rules       *RuleSet // Rules used to classify lines added later
+	traceOpen   bool     // The last trace may still receive frames
+	traceHeader int      // 1-based entry index of a line that may head a trace
+
+	signatureIndex map[int][]int // Signature indexes by token count
 }
 
 // Model represents the application state
 type Model struct {
+	client       ClusterClient
+	kubeOptions  KubeOptions        // Kubeconfig and context given on the command line
+	kubeContext  string             // Active kubeconfig context, "" when unknown
+	kubeContexts []string           // Contexts listed in the context picker
+	selectedKube int                // Cursor in the context picker
+	contextConf  *ContextConfig     // Production contexts and their banner
+	clusters     []cluster          // Contexts of the fan-out mode, nil for a single cluster
+	clusterErrs  map[string]error   // Contexts whose pods could not be loaded
+	podOptions   PodListOptions     // Selector of the listed pods
+	flatPods     bool               // Pod grid without workload groups
+	collapsed    map[string]bool    // Workload groups hidden behind their header
+	onGroup      bool               // The header of the selected pod's group is selected
+	workload     string             // Group analyzed as a whole, "" for a single pod
+	podErrors    map[string]podScan // Scanned error lines by podScanKey
+	scans        int                // Scans of the pods' logs in flight
+	filtering    bool               // The pod selector prompt is open
+	filterInput  string             // Selector typed in the prompt
+	filterErr    error              // Invalid selector submitted in the prompt
+	picking      bool               // The time window picker is open
+	pickCursor   int                // Selected preset, len(timePresets) for a custom range
+	pickInput    string             // Custom range typed in the picker
+	pickErr      error              // Invalid custom range submitted in the picker
+	allPods      []PodInfo          // Loaded pods, before the name filter
+	podQuery     string             // Name filter of the pod grid
+	sortMode     int                // Order of the pods within their group
+	loadedNS     []string           // Loaded namespaces, before the name filter
+	nsQuery      string             // Name filter of the namespace list
+	querying     bool               // The name filter prompt is open
+	rules        *RuleConfig
 	namespace    string
-	since        string
+	window       timeRange // Time range of the analyzed logs
 	namespaces   []string
 	pods         []PodInfo
 	selectedPod  int
 	selectedNS   int
+	selectedCtr  int    // Cursor in the container picker, 0 is "all containers"
+	container    string // Selected container name or AllContainers
 	logs         map[string]LogAnalysis
-	currentView  string // "namespaces", "pods", "analysis"
+	events       map[string]targetEvents
+	previousLogs map[string]LogAnalysis // Analysis of the previous container instance
+	showPrevious bool
+	currentView  string // "contexts", "namespaces", "pods", "containers", "analysis"
 	loading      bool
 	err          error
 	width        int
 	height       int
 	autoRefresh  bool
-	blinkState   bool // For blinking error indicator
-	pageOffset   int  // For pagination
-	logOffset    int  // For log scrolling
+	refresh      time.Duration // Auto-refresh interval
+	refreshing   bool          // A background refresh is in flight
+	refreshFails int           // Consecutive failed refreshes, for backoff
+	lastRefresh  time.Time     // Last successful load of the current view
+	lastAttempt  time.Time     // Last completed load, successful or not
+	blinkState   bool          // For blinking error indicator
+	pageOffset   int           // For pagination
+	logOffset    int           // For log scrolling
+	traceCursor  int           // 1-based stack trace selected in the log pane, 0 when none
+	timeCursor   int           // 1-based timeline column selected, 0 when none
+	expanded     map[int]bool  // Stack traces shown with their frames
+	logTab       int           // Active tab of the log pane
+	tabCursor    int           // Selected line in a category tab
+	contextLine  int           // Line opened in context, 0 when none
+	contextSize  int           // Lines shown around the context line, 0 for the default
+	jumping      bool          // The go to line prompt is open
+	jumpInput    string
+	searching    bool // The search prompt is open
+	searchQuery  string
+	searchRegex  bool           // Query is a regular expression rather than plain text
+	searchFilter bool           // Hide lines that do not match the query
+	search       *regexp.Regexp // Compiled query, nil when there is none
+	searchErr    error          // Invalid regular expression
+	matches      []int          // Entry indexes matching the query
+	matchCursor  int            // Current position in matches
+	follower     *logFollower
+	diffBase     *PodInfo   // Pod marked as the first input of a diff
+	diff         *podDiff   // Shown diff
+	baseRange    *timeRange // Time range of the diff's base side picked with b, nil for the default
+	pickBase     bool       // The time range picker edits baseRange
 	language     Language
 	localization Localization
 }
@@ -55,15 +187,64 @@ type LoadNamespacesMsg struct {
 	err        error
 }
 
+type LoadContextsMsg struct {
+	contexts []string
+	current  string // Current context of the kubeconfig
+	err      error
+}
+
+// SwitchContextMsg carries a client connected to another context
+type SwitchContextMsg struct {
+	client  ClusterClient
+	context string
+	err     error
+}
+
 type LoadPodsMsg struct {
-	pods []PodInfo
-	err  error
+	pods        []PodInfo
+	clusterErrs map[string]error // Contexts that failed in fan-out mode
+	err         error
+}
+
+// ScanPodsMsg carries the error line counts of scanned pods
+type ScanPodsMsg struct {
+	window timeRange // Time range the lines were counted in
+	scans  map[string]podScan
 }
 
 type LoadLogsMsg struct {
-	pod      string
+	target   logTarget
 	analysis LogAnalysis
+	previous *LogAnalysis
+	events   targetEvents
+	err      error
+}
+
+// LogLinesMsg carries lines streamed by a follower
+type LogLinesMsg struct {
+	follower *logFollower
+	lines    []timedLine
+}
+
+// FollowStoppedMsg is sent when a follow stream ends
+type FollowStoppedMsg struct {
+	follower *logFollower
 	err      error
 }
 
+// RefreshLogsMsg carries the lines logged since the last analysis of a target
+type RefreshLogsMsg struct {
+	target logTarget
+	lines  []timedLine
+	events targetEvents
+	at     time.Time // When the lines were fetched
+	err    error
+}
+
+// DiffMsg carries the compared error signatures of two pods or windows
+type DiffMsg struct {
+	diff podDiff
+	err  error
+}
+
 type TickMsg time.Time
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// scrollLog moves the log pane by delta lines, positive towards the end of
+// the log. The All tab scrolls its viewport; category tabs and the context
+// view move their cursor instead.
+func (m *Model) scrollLog(delta int) {
+	if m.contextLine > 0 || m.logTab != TabAll {
+		m.moveLogCursor(delta)
+		return
+	}
+
+	analysis, ok := m.shownAnalysis()
+	if !ok {
+		return
+	}
+	maxOffset := max(0, len(m.visibleLogEntries(analysis))-m.logPaneHeight())
+	m.logOffset = max(0, min(maxOffset, m.logOffset-delta))
+}
+
+// handleJumpKey edits the line number of the go to line prompt
+func (m Model) handleJumpKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		m.stopFollow()
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.jumping = false
+		m.jumpInput = ""
+	case tea.KeyEnter:
+		m.jumping = false
+		m.jumpToLine()
+		m.jumpInput = ""
+	case tea.KeyBackspace:
+		if len(m.jumpInput) > 0 {
+			m.jumpInput = m.jumpInput[:len(m.jumpInput)-1]
+		}
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			if r >= '0' && r <= '9' {
+				m.jumpInput += string(r)
+			}
+		}
+	}
+	return m, nil
+}
+
+// jumpToLine scrolls the All tab to the line number typed in the prompt,
+// clamped to the log
+func (m *Model) jumpToLine() {
+	line, err := strconv.Atoi(m.jumpInput)
+	analysis, ok := m.shownAnalysis()
+	if err != nil || !ok || len(analysis.Entries) == 0 {
+		return
+	}
+	index := max(0, min(len(analysis.Entries)-1, line-1))
+	if m.searchFilter {
+		// The line may be hidden by the filter
+		m.searchFilter = false
+	}
+	m.scrollToEntry(analysis, index)
+}
+
+// logPosition describes which part of the log pane is on screen
+func logPosition(start, end, total int) string {
+	if total == 0 {
+		return "0/0"
+	}
+	percent := 100
+	if total > end-start {
+		percent = start * 100 / (total - (end - start))
+	}
+	return strconv.Itoa(start+1) + "-" + strconv.Itoa(end) + "/" + strconv.Itoa(total) + " (" + strconv.Itoa(percent) + "%)"
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLogPosition(t *testing.T) {
+	tests := []struct {
+		start, end, total int
+		want              string
+	}{
+		{0, 0, 0, "0/0"},
+		{0, 5, 5, "1-5/5 (100%)"},
+		{0, 10, 100, "1-10/100 (0%)"},
+		{45, 55, 100, "46-55/100 (50%)"},
+		{90, 100, 100, "91-100/100 (100%)"},
+	}
+	for _, tt := range tests {
+		if got := logPosition(tt.start, tt.end, tt.total); got != tt.want {
+			t.Errorf("logPosition(%d, %d, %d) = %q, want %q", tt.start, tt.end, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestJumpToLine(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 100; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+
+	tests := []struct {
+		input string
+		want  int // Entry shown on screen
+	}{
+		{"1", 0},
+		{"50", 49},
+		{"100", 99},
+		{"0", 0},     // Clamped to the first line
+		{"9999", 99}, // Clamped to the last line
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			m := analysisModel(lines...)
+			m.searchFilter = true
+			m.jumpInput = tt.input
+			m.jumpToLine()
+
+			analysis, _ := m.shownAnalysis()
+			visible, start, end := m.logWindow(analysis)
+			if tt.want < visible[start] || tt.want > visible[end-1] {
+				t.Errorf("lines %d-%d on screen, want %d among them", visible[start]+1, visible[end-1]+1, tt.want+1)
+			}
+			if m.searchFilter {
+				t.Error("filter mode still on, the line may be hidden")
+			}
+		})
+	}
+
+	m := analysisModel(lines...)
+	m.logOffset = 7
+	m.jumpInput = ""
+	m.jumpToLine()
+	if m.logOffset != 7 {
+		t.Errorf("empty input scrolled to offset %d", m.logOffset)
+	}
+}
@@ -2,16 +2,58 @@ package main
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
+// RenderContextsView renders the kubeconfig context picker
+func (m Model) RenderContextsView() string {
+	title := m.viewTitle(m.localization.ContextSelectionTitle)
+
+	var content strings.Builder
+	content.WriteString(title + "\n\n")
+
+	if len(m.kubeContexts) == 0 {
+		content.WriteString(m.localization.ContextNotFound + "\n")
+	}
+	for i, name := range m.kubeContexts {
+		prefix := "  "
+		style := NormalStyle
+		if i == m.selectedKube {
+			prefix = "> "
+			style = SelectedStyle
+		}
+
+		line := prefix + style.Render(name)
+		if slices.Contains(m.activeContexts(), name) {
+			line += " " + InfoStyle.Render("● "+m.localization.ActiveContext)
+		}
+		if m.contextConf.IsProduction(name) {
+			line += " " + m.contextConf.style().Render("⚠ "+m.localization.Production)
+		}
+		content.WriteString(line + "\n")
+	}
+
+	content.WriteString("\n" + m.localization.Controls + ":\n")
+	content.WriteString("  " + m.localization.Movement + "\n")
+	content.WriteString("  " + m.localization.SelectContext + "\n")
+	content.WriteString("  " + m.localization.GoBack + "\n")
+	content.WriteString("  " + m.localization.Refresh + "\n")
+	content.WriteString("  " + m.localization.Exit)
+
+	return BorderStyle.Render(content.String())
+}
+
 // RenderNamespacesView renders the namespace selection view
 func (m Model) RenderNamespacesView() string {
-	title := TitleStyle.Render(m.localization.NamespaceSelectionTitle)
+	title := m.viewTitle(m.localization.NamespaceSelectionTitle)
 
 	var content strings.Builder
 	content.WriteString(title + "\n\n")
+	content.WriteString(m.queryStatus(m.nsQuery, len(m.namespaces), len(m.loadedNS)))
 
 	if len(m.namespaces) == 0 {
 		content.WriteString(m.localization.NamespaceNotFound + "\n")
@@ -48,10 +90,12 @@ func (m Model) RenderNamespacesView() string {
 		}
 	}
 
-	content.WriteString(fmt.Sprintf("\n%s: %t\n", m.localization.AutoRefreshStatus, m.autoRefresh))
+	content.WriteString("\n" + m.refreshStatus() + "\n")
 	content.WriteString("\n" + m.localization.Controls + ":\n")
 	content.WriteString("  " + m.localization.Movement + "\n")
 	content.WriteString("  " + m.localization.Select + "\n")
+	content.WriteString("  " + m.localization.FilterNames + "\n")
+	content.WriteString("  " + m.localization.ContextPicker + "\n")
 	content.WriteString("  " + m.localization.Refresh + "\n")
 	content.WriteString("  " + m.localization.AutoRefresh + "\n")
 	content.WriteString("  " + m.localization.Exit)
@@ -61,10 +105,24 @@ func (m Model) RenderNamespacesView() string {
 
 // RenderPodsView renders the pod list view
 func (m Model) RenderPodsView() string {
-	title := TitleStyle.Render(fmt.Sprintf("%s: %s", m.localization.NamespaceTitle, m.namespace))
+	titleText := fmt.Sprintf("%s: %s", m.localization.NamespaceTitle, m.namespace)
+	if selector := m.podOptions.String(); selector != "" {
+		titleText += fmt.Sprintf(" (%s: %s)", m.localization.Selector, selector)
+	}
+	titleText += fmt.Sprintf(" (%s: %s)", m.localization.TimeWindow, m.window)
+	title := m.viewTitle(titleText)
 
 	var content strings.Builder
 	content.WriteString(title + "\n\n")
+	content.WriteString(m.selectorStatus())
+	content.WriteString(m.timeStatus())
+	content.WriteString(m.queryStatus(m.podQuery, len(m.pods), len(m.allPods)))
+	content.WriteString(m.scanStatus())
+	content.WriteString(m.diffStatus())
+
+	if len(m.clusters) > 0 {
+		content.WriteString(m.renderClusterSummaries() + "\n")
+	}
 
 	if len(m.pods) == 0 {
 		content.WriteString(m.localization.PodNotFound + "\n")
@@ -81,65 +139,77 @@ func (m Model) RenderPodsView() string {
 		availableForPods := availableWidth - totalSpacing
 		podWidth := min(maxPodWidth, availableForPods/podsPerRow)
 
-		// Calculate how many rows we can display based on available height
-		// Each pod row takes about 8 lines (pod box height + spacing)
+		// Calculate how many lines we can display based on available height
+		// Each pod row takes about 8 lines (pod box height + spacing) and
+		// one more for status reasons, a group header one
 		podRowHeight := 8
-		availableHeight := m.height - 15 // Reserve space for title, controls, etc.
-		maxVisibleRows := max(1, availableHeight/podRowHeight)
+		availableHeight := max(podRowHeight, m.height-15) // Reserve space for title, controls, etc.
+		rowHeight := func(row gridRow) int {
+			if row.header {
+				return 1
+			}
+			return podRowHeight + m.rowBoxLines(row) - podBoxLines
+		}
 
 		// Calculate total rows and pagination
-		totalRows := (len(m.pods) + podsPerRow - 1) / podsPerRow
+		rows := m.podRows()
+		totalRows := len(rows)
 
-		// Calculate scroll offset in terms of rows
-		selectedRow := m.selectedPod / podsPerRow
+		// Scroll just far enough to keep the selected row visible
+		selectedRow, _ := m.gridCursor(rows)
 		scrollOffset := 0
-
-		if totalRows > maxVisibleRows {
-			// Calculate scroll offset to keep selected pod visible
-			if selectedRow >= maxVisibleRows {
-				scrollOffset = selectedRow - maxVisibleRows + 1
-			}
-			if scrollOffset > totalRows-maxVisibleRows {
-				scrollOffset = totalRows - maxVisibleRows
+		used := 0
+		for i := 0; i <= selectedRow; i++ {
+			used += rowHeight(rows[i])
+		}
+		for used > availableHeight && scrollOffset < selectedRow {
+			used -= rowHeight(rows[scrollOffset])
+			scrollOffset++
+		}
+		endRowIndex := scrollOffset
+		for used = 0; endRowIndex < totalRows; endRowIndex++ {
+			used += rowHeight(rows[endRowIndex])
+			if used > availableHeight && endRowIndex > scrollOffset {
+				break
 			}
 		}
 
 		// Show pagination info if needed
-		if totalRows > maxVisibleRows {
-			startRow := scrollOffset + 1
-			endRow := min(totalRows, scrollOffset+maxVisibleRows)
+		if scrollOffset > 0 || endRowIndex < totalRows {
 			content.WriteString(fmt.Sprintf("Rows %d-%d / %d total (%d pods)\n",
-				startRow, endRow, totalRows, len(m.pods)))
+				scrollOffset+1, endRowIndex, totalRows, len(m.pods)))
 			if scrollOffset > 0 {
 				content.WriteString(m.localization.ScrollUp + "\n")
 			}
-			if scrollOffset+maxVisibleRows < totalRows {
+			if endRowIndex < totalRows {
 				content.WriteString(m.localization.ScrollDown + "\n")
 			}
 			content.WriteString("\n")
 		}
 
-		content.WriteString(m.localization.Pods + ":\n\n")
+		content.WriteString(fmt.Sprintf("%s:  %s\n\n", m.localization.Pods,
+			NormalStyle.Render(m.localization.SortBy+": "+m.sortLabel(m.sortMode))))
 
 		// Render visible rows only
-		startRowIndex := scrollOffset
-		endRowIndex := min(totalRows, scrollOffset+maxVisibleRows)
-
-		for rowIndex := startRowIndex; rowIndex < endRowIndex; rowIndex++ {
-			startPodIndex := rowIndex * podsPerRow
-			endPodIndex := min(len(m.pods), startPodIndex+podsPerRow)
+		for rowIndex := scrollOffset; rowIndex < endRowIndex; rowIndex++ {
+			row := rows[rowIndex]
+			if row.header {
+				content.WriteString(m.renderGroupHeader(row, rowIndex == selectedRow) + "\n")
+				continue
+			}
 
 			// Create a row of pods
 			var rowBoxes []string
+			lines := m.rowBoxLines(row)
 
-			for podIndex := startPodIndex; podIndex < endPodIndex; podIndex++ {
+			for _, podIndex := range row.pods {
 				pod := m.pods[podIndex]
 
 				// Determine if this pod is selected
-				isSelected := podIndex == m.selectedPod
+				isSelected := podIndex == m.selectedPod && !m.onGroup
 
 				// Create individual pod box with calculated width
-				podBox := m.renderPodBox(pod, isSelected, podWidth)
+				podBox := m.renderPodBox(pod, isSelected, podWidth, lines)
 				rowBoxes = append(rowBoxes, podBox)
 			}
 
@@ -150,13 +220,22 @@ func (m Model) RenderPodsView() string {
 		}
 	}
 
-	content.WriteString(fmt.Sprintf("\n%s: %t\n", m.localization.AutoRefreshStatus, m.autoRefresh))
+	content.WriteString("\n" + m.refreshStatus() + "\n")
 	content.WriteString("\n" + m.localization.Controls + ":\n")
 	content.WriteString("  " + m.localization.UpDown + ": Navigate rows\n")
 	content.WriteString("  " + m.localization.LeftRight + ": Navigate columns\n")
 	content.WriteString("  Page Up/Down, Ctrl+U/D: Fast scroll\n")
 	content.WriteString("  Home/End, g/G: Go to first/last pod\n")
 	content.WriteString("  " + m.localization.ViewLogs + "\n")
+	content.WriteString("  " + m.localization.AnalyzeWorkload + "\n")
+	content.WriteString("  " + m.localization.AnalyzeAll + "\n")
+	content.WriteString("  " + m.localization.FilterPods + "\n")
+	content.WriteString("  " + m.localization.FilterNames + "\n")
+	content.WriteString("  " + m.localization.CycleSort + "\n")
+	content.WriteString("  " + m.localization.CollapseGroups + "\n")
+	content.WriteString("  " + m.localization.GroupByWorkload + "\n")
+	content.WriteString("  " + m.localization.DiffPods + "\n")
+	content.WriteString("  " + m.localization.PickTime + "\n")
 	content.WriteString("  Esc/Backspace: " + m.localization.NamespaceTitle + "\n")
 	content.WriteString("  " + m.localization.Refresh + "\n")
 	content.WriteString("  " + m.localization.AutoRefresh + "\n")
@@ -165,93 +244,264 @@ func (m Model) RenderPodsView() string {
 	return BorderStyle.Render(content.String())
 }
 
+// RenderContainersView renders the container picker of the selected pod
+func (m Model) RenderContainersView() string {
+	pod := m.pods[m.selectedPod]
+	title := m.viewTitle(fmt.Sprintf("%s: %s", m.localization.ContainersTitle, pod.Name))
+
+	var content strings.Builder
+	content.WriteString(title + "\n\n")
+
+	for i := 0; i <= len(pod.Containers); i++ {
+		prefix := "  "
+		style := NormalStyle
+		if i == m.selectedCtr {
+			prefix = "> "
+			style = SelectedStyle
+		}
+
+		if i == 0 {
+			content.WriteString(fmt.Sprintf("%s%s\n", prefix, style.Render(m.localization.AllContainers)))
+			continue
+		}
+
+		container := pod.Containers[i-1]
+		content.WriteString(fmt.Sprintf("%s%-30s %-10s %s %s\n",
+			prefix,
+			style.Render(container.Name),
+			"["+container.Type+"]",
+			GetStatusStyle(container.State).Render(container.State),
+			InfoStyle.Render(fmt.Sprintf("%d restarts", container.Restarts)),
+		))
+	}
+
+	content.WriteString("\n" + m.localization.Controls + ":\n")
+	content.WriteString("  " + m.localization.Movement + "\n")
+	content.WriteString("  " + m.localization.SelectContainer + "\n")
+	content.WriteString("  " + m.localization.GoBack + "\n")
+	content.WriteString("  " + m.localization.Exit)
+
+	return BorderStyle.Render(content.String())
+}
+
 // RenderAnalysisView renders the log analysis view
 func (m Model) RenderAnalysisView() string {
 	selectedPod := m.pods[m.selectedPod].Name
-	analysis, exists := m.logs[selectedPod]
+	target := m.currentTarget()
+	analysis, exists := m.logs[target.key()]
+	previous, hasPrevious := m.previousLogs[target.key()]
+	if m.showPrevious && hasPrevious {
+		analysis = previous
+	}
 
 	if !exists {
 		return BorderStyle.Render(m.localization.LogNotFound + "\n\n" + m.localization.Loading)
 	}
 
-	title := TitleStyle.Render(fmt.Sprintf("%s: %s", m.localization.LogAnalysisTitle, selectedPod))
+	titleText := selectedPod
+	if target.workload != "" {
+		titleText = m.workloadTitle(target)
+	} else if context := m.pods[m.selectedPod].Context; context != "" {
+		titleText = context + "/" + titleText
+	}
+	if m.container == AllContainers && target.workload == "" {
+		titleText += " (" + m.localization.AllContainers + ")"
+	} else if m.container != "" && len(m.pods[m.selectedPod].Containers) > 1 {
+		titleText += " / " + m.container
+	}
+	titleText += fmt.Sprintf(" (%s: %s)", m.localization.TimeWindow, m.window)
+	title := m.viewTitle(fmt.Sprintf("%s: %s", m.localization.LogAnalysisTitle, titleText))
+	if m.follower != nil {
+		title += " " + InfoStyle.Render("● "+m.localization.Following)
+	}
 
 	var content strings.Builder
 	content.WriteString(title + "\n\n")
+	content.WriteString(m.timeStatus())
 
-	// Pod bilgileri (sadeleştirilmiş)
-	selectedPodInfo := m.pods[m.selectedPod]
-	content.WriteString(m.localization.PodDetails + ":\n")
-	content.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Name, SelectedStyle.Render(selectedPodInfo.Name)))
-	content.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Status, GetStatusStyle(selectedPodInfo.Status).Render(selectedPodInfo.Status)))
-	content.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Ready, selectedPodInfo.Ready))
-	content.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Restart, selectedPodInfo.Restarts))
-	content.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Age, selectedPodInfo.Age))
-	content.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Analysis, analysis.AnalyzedAt.Format("15:04:05")))
+	// When the lines were logged, at the top so bursts stand out
+	timeline := m.renderTimeline(analysis)
+	if timeline != "" {
+		content.WriteString(timeline + "\n")
+	}
+
+	details := m.renderPodDetails(target, analysis)
+	if target.workload != "" {
+		details = m.renderWorkloadDetails(target, analysis)
+	}
+	// Events beside the details when they fit, below them otherwise
+	details = strings.TrimSuffix(details, "\n")
+	if panelWidth := m.width - 13 - lipgloss.Width(details); panelWidth >= minEventPanelWidth {
+		panel := strings.TrimSuffix(m.renderEventsPanel(target, panelWidth), "\n")
+		content.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, details, "   ", panel) + "\n")
+	} else {
+		content.WriteString(details + "\n\n" + m.renderEventsPanel(target, m.width-10))
+	}
 	content.WriteString("\n")
 
+	// Current / previous instance panes
+	if hasPrevious {
+		current := "[" + m.localization.CurrentInstance + "]"
+		prev := " " + m.localization.PreviousInstance + " "
+		if m.showPrevious {
+			current = " " + m.localization.CurrentInstance + " "
+			prev = "[" + m.localization.PreviousInstance + "]"
+			content.WriteString(NormalStyle.Render(current) + " " + SelectedStyle.Render(prev) + "\n\n")
+		} else {
+			content.WriteString(SelectedStyle.Render(current) + " " + NormalStyle.Render(prev) + "\n\n")
+		}
+	}
+
 	// Log özeti (sadeleştirilmiş)
 	content.WriteString(m.localization.LogSummary + ":\n")
 	content.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.TotalLines, InfoStyle.Render(strconv.Itoa(analysis.TotalLines))))
+	if formats := formatSummary(analysis.FormatCounts); formats != "" {
+		content.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.LogFormats, formats))
+	}
 	if analysis.ErrorCount > 0 {
 		content.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Errors, ErrorStyle.Render(strconv.Itoa(analysis.ErrorCount))))
 	}
 	if analysis.WarningCount > 0 {
 		content.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Warnings, WarningStyle.Render(strconv.Itoa(analysis.WarningCount))))
 	}
+	if len(analysis.Traces) > 0 {
+		content.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.StackTraces, ErrorStyle.Render(strconv.Itoa(len(analysis.Traces)))))
+	}
 	content.WriteString("\n")
 
-	// MAIN SECTION: RAW LOG LINES
-	if analysis.RawLogs != "" {
-		content.WriteString(m.localization.LogLines + ":\n")
-		content.WriteString(strings.Repeat("-", min(80, m.width-10)) + "\n")
+	// Error and warning rules that matched, with their remediation hints
+	var ruleLines []string
+	for _, rule := range m.rules.ForNamespace(m.namespace).rules {
+		hits := analysis.RuleHits[rule.Name]
+		if hits == 0 || rule.Category == CategoryInfo {
+			continue
+		}
+		style := WarningStyle
+		if rule.Category == CategoryError {
+			style = ErrorStyle
+		}
+		line := fmt.Sprintf("  %s [%s]: %d", style.Render(rule.Name), rule.Severity, hits)
+		if rule.Hint != "" {
+			line += "\n    → " + m.truncateLogLine(rule.Hint, max(10, m.width-12))
+		}
+		ruleLines = append(ruleLines, line)
+	}
+	if len(ruleLines) > 0 {
+		content.WriteString(m.localization.MatchedRules + ":\n")
+		content.WriteString(strings.Join(ruleLines, "\n") + "\n\n")
+	}
 
-		lines := strings.Split(strings.TrimSpace(analysis.RawLogs), "\n")
+	// Most frequent error templates
+	if signatures := analysis.TopSignatures(maxTopSignatures); len(signatures) > 0 {
+		content.WriteString(m.localization.TopSignatures + ":\n")
+		content.WriteString(fmt.Sprintf("  %6s  %-12s  %-12s  %s\n",
+			m.localization.Count, m.localization.FirstSeen, m.localization.LastSeen, m.localization.Signature))
+		for i, sig := range signatures {
+			// Numbered for the replica breakdown of a workload
+			number := ""
+			if target.workload != "" {
+				number = fmt.Sprintf("#%d ", i+1)
+			}
+			content.WriteString(fmt.Sprintf("  %s  %-12s  %-12s  %s%s\n",
+				ErrorStyle.Render(fmt.Sprintf("%6d", sig.Count)),
+				seenAt(sig.FirstSeen, sig.FirstLine), seenAt(sig.LastSeen, sig.LastLine),
+				number, m.truncateLogLine(sig.Template, max(10, m.width-48-len(number)))))
+			if sig.Count > 1 {
+				content.WriteString(fmt.Sprintf("  %34s  %s\n", m.localization.Example,
+					NormalStyle.Render(m.truncateLogLine(strings.TrimSpace(sig.Example), max(10, m.width-48)))))
+			}
+		}
+		content.WriteString("\n")
+
+		if target.workload != "" {
+			content.WriteString(m.renderReplicaBreakdown(analysis, target, signatures) + "\n")
+		}
+	}
+
+	// MAIN SECTION: RAW LOG LINES
+	if len(analysis.Entries) > 0 {
+		// Category tabs with their line counts
+		var tabs []string
+		for tab := TabAll; tab < tabCount; tab++ {
+			count := len(analysis.Entries)
+			if tab != TabAll {
+				count = len(analysis.tabEntries(tab))
+			}
+			label := fmt.Sprintf("%s %d", m.tabLabel(tab), count)
+			if tab == m.logTab {
+				tabs = append(tabs, SelectedStyle.Render("["+label+"]"))
+			} else {
+				tabs = append(tabs, NormalStyle.Render(" "+label+" "))
+			}
+		}
+		content.WriteString(strings.Join(tabs, " ") + "\n\n")
 
-		// Calculate visible lines based on terminal height
-		maxVisibleLines := max(10, (m.height - 25)) // Reserve space for other UI elements
-		totalLines := len(lines)
+		header := m.localization.LogLines + ":"
+		if m.contextLine > 0 {
+			header = fmt.Sprintf("%s: #%d (±%d)", m.localization.Context, m.contextLine, m.contextLines())
+		}
+		if status := m.searchStatus(); status != "" {
+			header += " " + status
+		}
+		if m.jumping {
+			header += " " + SelectedStyle.Render(":"+m.jumpInput+"█") + " " + NormalStyle.Render(m.localization.GoToLine)
+		}
+		content.WriteString(header + "\n")
+		content.WriteString(strings.Repeat("-", min(80, m.width-10)) + "\n")
 
-		// Apply scroll offset
-		startIdx := max(0, totalLines-maxVisibleLines-m.logOffset)
-		endIdx := min(totalLines, startIdx+maxVisibleLines)
+		// Frames of collapsed stack traces are hidden behind their header
+		entries, startIdx, endIdx, cursor := m.logPane(analysis)
+		maxVisibleLines := m.logPaneHeight()
+		totalLines := len(entries)
 
 		// Show pagination info for logs
 		if totalLines > maxVisibleLines {
-			content.WriteString(fmt.Sprintf("Showing lines %d-%d of %d total lines\n",
-				startIdx+1, endIdx, totalLines))
-			if m.logOffset > 0 {
+			content.WriteString(fmt.Sprintf("%s %s\n", m.localization.Position, logPosition(startIdx, endIdx, totalLines)))
+			if startIdx > 0 {
 				content.WriteString("↑ Scroll up for more logs\n")
 			}
-			if startIdx > 0 {
+			if endIdx < totalLines {
 				content.WriteString("↓ Scroll down for more logs\n")
 			}
 			content.WriteString(strings.Repeat("-", min(80, m.width-10)) + "\n")
 		}
 
-		for i := startIdx; i < endIdx; i++ {
-			line := strings.TrimSpace(lines[i])
-			if line == "" {
-				continue
+		if totalLines == 0 {
+			content.WriteString(NormalStyle.Render("  "+m.localization.NoLines) + "\n")
+		}
+		// Merged workload logs show their pod in a column of its own
+		sourceCol := 0
+		if target.workload != "" {
+			sourceCol = sourceWidth(target)
+		}
+		// Warning events are shown between the lines logged around them
+		var slots map[int][]PodEvent
+		if m.contextLine > 0 || m.logTab == TabAll && !(m.searchFilter && m.search != nil) {
+			slots = m.eventSlots(analysis, target)
+		}
+		writeEvents := func(from, to int) {
+			for slot := from; slot <= to && len(slots) > 0; slot++ {
+				for _, event := range slots[slot] {
+					content.WriteString(m.renderEventLine(event, target) + "\n")
+				}
 			}
-
-			// Satır numarası ile birlikte göster
-			lineNum := i + 1
-			truncatedLine := m.truncateLogLine(line, m.width-15)
-
-			// Log seviyesine göre renklendirme (icon olmadan)
-			lowerLine := strings.ToLower(line)
-			if strings.Contains(lowerLine, "error") || strings.Contains(lowerLine, "fail") ||
-				strings.Contains(lowerLine, "exception") || strings.Contains(lowerLine, "panic") {
-				content.WriteString(fmt.Sprintf("  %4d: %s\n", lineNum, ErrorStyle.Render(truncatedLine)))
-			} else if strings.Contains(lowerLine, "warn") || strings.Contains(lowerLine, "warning") {
-				content.WriteString(fmt.Sprintf("  %4d: %s\n", lineNum, WarningStyle.Render(truncatedLine)))
-			} else if strings.Contains(lowerLine, "info") {
-				content.WriteString(fmt.Sprintf("  %4d: %s\n", lineNum, InfoStyle.Render(truncatedLine)))
-			} else {
-				content.WriteString(fmt.Sprintf("  %4d: %s\n", lineNum, NormalStyle.Render(truncatedLine)))
+		}
+		previous := -1
+		if startIdx > 0 {
+			previous = entries[startIdx-1]
+		} else if len(entries) > 0 {
+			previous = entries[0] - 1
+		}
+		for _, index := range entries[startIdx:endIdx] {
+			writeEvents(previous+1, index)
+			previous = index
+			if line := m.renderLogLine(analysis, index, index == cursor, sourceCol); line != "" {
+				content.WriteString(line + "\n")
 			}
 		}
+		if endIdx == totalLines && (m.contextLine == 0 || previous == len(analysis.Entries)-1) {
+			writeEvents(previous+1, len(analysis.Entries))
+		}
 
 		content.WriteString(strings.Repeat("-", min(80, m.width-10)) + "\n")
 		content.WriteString(fmt.Sprintf("%s %d %s %d %s\n\n",
@@ -271,9 +521,114 @@ func (m Model) RenderAnalysisView() string {
 
 	content.WriteString(m.localization.Controls + ":\n")
 	content.WriteString("  " + m.localization.UpDown + ": " + m.localization.ScrollUp + "/" + m.localization.ScrollDown + "\n")
+	content.WriteString("  " + m.localization.ScrollControls + "\n")
 	content.WriteString("  " + m.localization.GoBack + "\n")
 	content.WriteString("  " + m.localization.RefreshLogs + "\n")
+	if !m.window.closed() {
+		content.WriteString("  " + m.localization.FollowLogs + "\n")
+	}
+	content.WriteString("  " + m.localization.PickTime + "\n")
+	if hasPrevious {
+		content.WriteString("  " + m.localization.TogglePrevious + "\n")
+	}
+	if len(analysis.Traces) > 0 {
+		content.WriteString("  " + m.localization.TraceControls + "\n")
+	}
+	if timeline != "" {
+		content.WriteString("  " + m.localization.TimelineControls + "\n")
+	}
+	content.WriteString("  " + m.localization.TabControls + "\n")
+	content.WriteString("  " + m.localization.SearchControls + "\n")
 	content.WriteString("  " + m.localization.Exit)
 
 	return BorderStyle.Render(content.String())
 }
+
+// renderLogLine renders a log entry with its line number, colored by the
+// category the analyzer gave it. Stack trace headers show whether their
+// frames are expanded; expanded frames are indented under them. With a
+// source column width, the source tag of the line is moved into a column.
+func (m Model) renderLogLine(analysis LogAnalysis, index int, selected bool, sourceCol int) string {
+	entry := analysis.Entries[index]
+	line := strings.TrimSpace(entry.Raw)
+	if line == "" {
+		return ""
+	}
+
+	marker := "  "
+	if selected {
+		marker = SelectedStyle.Render("▶ ")
+	}
+
+	source := ""
+	if sourceCol > 0 && entry.Source != "" {
+		_, rest := splitTag(line)
+		line = strings.TrimSpace(rest)
+		tag := []rune(entry.Source)
+		if len(tag) > sourceCol {
+			tag = append(tag[:sourceCol-1], '…')
+		}
+		source = InfoStyle.Render(fmt.Sprintf("%-*s", sourceCol, string(tag))) + " │ "
+		sourceCol += 3
+	}
+
+	if entry.Trace > 0 && !analysis.isTraceHeader(entry) {
+		truncatedLine := m.truncateLogLine(line, max(10, m.width-19-sourceCol))
+		return fmt.Sprintf("%s%4d: %s    %s", marker, entry.Line, source, m.highlightMatches(truncatedLine, NormalStyle))
+	}
+
+	suffix := ""
+	if analysis.isTraceHeader(entry) {
+		if entry.Trace == m.traceCursor {
+			marker = SelectedStyle.Render("▶ ")
+		}
+		if m.expanded[entry.Trace] {
+			suffix = " ▾"
+		} else {
+			suffix = fmt.Sprintf(" ▸ +%d", len(analysis.Traces[entry.Trace-1].Frames))
+		}
+	}
+	truncatedLine := m.truncateLogLine(line, max(10, m.width-15-sourceCol-len([]rune(suffix))))
+	if suffix != "" {
+		suffix = NormalStyle.Render(suffix)
+	}
+
+	return fmt.Sprintf("%s%4d: %s%s%s", marker, entry.Line, source, m.highlightMatches(truncatedLine, categoryStyle(entry.Category)), suffix)
+}
+
+// renderPodDetails renders the details of the analyzed pod
+func (m Model) renderPodDetails(target logTarget, analysis LogAnalysis) string {
+	var b strings.Builder
+
+	// Pod bilgileri (sadeleştirilmiş)
+	selectedPodInfo := m.pods[m.selectedPod]
+	b.WriteString(m.localization.PodDetails + ":\n")
+	b.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Name, SelectedStyle.Render(selectedPodInfo.Name)))
+	b.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Status, GetStatusStyle(selectedPodInfo.Status).Render(selectedPodInfo.Status)))
+	if selectedPodInfo.Reason != "" {
+		b.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Reason, WarningStyle.Render(selectedPodInfo.Reason)))
+	}
+	b.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Ready, selectedPodInfo.Ready))
+	b.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Restart, selectedPodInfo.Restarts))
+	b.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Age, selectedPodInfo.Age))
+	b.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Analysis, analysis.AnalyzedAt.Format("15:04:05")))
+	b.WriteString("  " + m.refreshStatus() + "\n")
+	for _, container := range targetContainers(selectedPodInfo, target) {
+		last := container.LastTermination
+		if last == nil {
+			continue
+		}
+		label := m.localization.LastTermination
+		if len(target.containers) > 1 {
+			label += " (" + container.Name + ")"
+		}
+		b.WriteString(fmt.Sprintf("  %s: %s, %s %d, %s %d, %s %s\n",
+			label,
+			FailedStyle.Render(last.Reason),
+			m.localization.ExitCode, last.ExitCode,
+			m.localization.Signal, last.Signal,
+			m.localization.FinishedAt, last.FinishedAt.Local().Format("2006-01-02 15:04:05"),
+		))
+	}
+	return b.String()
+}
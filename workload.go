@@ -0,0 +1,495 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// podGroup returns the key of the group a pod is listed under in the pod
+// grid: its cluster, and its workload unless the grid is flat
+func (m Model) podGroup(pod PodInfo) string {
+	if m.flatPods {
+		return pod.Context
+	}
+	return pod.Context + "\x00" + pod.Workload
+}
+
+// groupLabel returns the name shown in the header of a pod's group
+func (m Model) groupLabel(pod PodInfo) string {
+	if m.flatPods {
+		return pod.Context
+	}
+	label := pod.Workload
+	if label == "" {
+		label = m.localization.Standalone
+	}
+	if pod.Context != "" {
+		label = pod.Context + " · " + label
+	}
+	return label
+}
+
+// setPods replaces the loaded pods, keeping the same pod selected
+func (m *Model) setPods(pods []PodInfo) {
+	m.allPods = pods
+	m.applyPodFilter()
+}
+
+// toggleFlatPods switches between the grouped and the flat pod grid
+func (m *Model) toggleFlatPods() {
+	selected, ok := m.selected()
+	m.flatPods = !m.flatPods
+	m.onGroup = false
+	m.sortPods()
+	if ok {
+		m.selectPod(selected)
+	}
+}
+
+// selected returns the selected pod
+func (m Model) selected() (PodInfo, bool) {
+	if m.selectedPod >= len(m.pods) {
+		return PodInfo{}, false
+	}
+	return m.pods[m.selectedPod], true
+}
+
+// selectPod moves the selection to a pod, if it is still listed
+func (m *Model) selectPod(pod PodInfo) {
+	for i, p := range m.pods {
+		if p.Name == pod.Name && p.Context == pod.Context {
+			m.selectedPod = i
+			return
+		}
+	}
+}
+
+// sortPods orders the pods so that every group is contiguous: by cluster in
+// the order the contexts were given, then by workload with standalone pods
+// last, then by the sort mode
+func (m *Model) sortPods() {
+	order := make(map[string]int, len(m.clusters))
+	for i, c := range m.clusters {
+		order[c.context] = i
+	}
+
+	sort.SliceStable(m.pods, func(i, j int) bool {
+		a, b := m.pods[i], m.pods[j]
+		if a.Context != b.Context {
+			return order[a.Context] < order[b.Context]
+		}
+		if !m.flatPods && a.Workload != b.Workload {
+			if a.Workload == "" || b.Workload == "" {
+				return b.Workload == ""
+			}
+			return a.Workload < b.Workload
+		}
+		return m.comparePods(a, b) < 0
+	})
+}
+
+// toggleGroup collapses or expands the group of the selected pod
+func (m *Model) toggleGroup() {
+	pod, ok := m.selected()
+	if !ok || m.flatPods {
+		return
+	}
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
+	}
+	group := m.podGroup(pod)
+	m.collapsed[group] = !m.collapsed[group]
+	// A hidden pod can not stay selected
+	m.onGroup = m.onGroup || m.collapsed[group]
+}
+
+// toggleAllGroups collapses every group, or expands them all when they
+// already are
+func (m *Model) toggleAllGroups() {
+	if m.flatPods {
+		return
+	}
+	collapse := false
+	for _, pod := range m.pods {
+		if !m.collapsed[m.podGroup(pod)] {
+			collapse = true
+			break
+		}
+	}
+
+	m.collapsed = make(map[string]bool)
+	if collapse {
+		for _, pod := range m.pods {
+			m.collapsed[m.podGroup(pod)] = true
+		}
+		m.onGroup = true
+	}
+}
+
+// podScan is the error line count of a pod's logs, -1 when they could not
+// be read, along with its restarts when they were counted: a restarted pod
+// is scanned again
+type podScan struct {
+	errors   int
+	restarts string
+	analyzed bool // Counted by the pod's last analysis, which scans keep
+}
+
+// maxScannedPods caps the pods whose logs are scanned in the background for
+// the pod grid, so a namespace of hundreds of pods does not fetch them all
+const maxScannedPods = 50
+
+// podScanKey identifies a scanned pod by context, namespace and name
+func podScanKey(context, namespace, pod string) string {
+	return context + "/" + namespace + "/" + pod
+}
+
+// podErrorCount returns the error lines counted in a pod's logs, or -1 when
+// they have not been scanned yet or could not be read
+func (m Model) podErrorCount(pod PodInfo) int {
+	if scan, ok := m.podErrors[podScanKey(pod.Context, m.namespace, pod.Name)]; ok {
+		return scan.errors
+	}
+	return -1
+}
+
+// recordAnalysis makes the error lines of a pod's last analysis its count
+// in the pod grid, so the pods sort by the errors the user saw. Workload
+// analyses leave the counts of their pods to the scan.
+func (m *Model) recordAnalysis(target logTarget, analysis LogAnalysis) {
+	if target.workload != "" {
+		return
+	}
+	scan := podScan{errors: analysis.ErrorCount, analyzed: true}
+	for _, pod := range m.allPods {
+		if pod.Name == target.pod && pod.Context == target.context {
+			scan.restarts = pod.Restarts
+		}
+	}
+	if m.podErrors == nil {
+		m.podErrors = make(map[string]podScan)
+	}
+	m.podErrors[podScanKey(target.context, m.namespace, target.pod)] = scan
+	m.applyPodFilter()
+}
+
+// unscannedPods returns the shown pods whose errors were not counted yet,
+// in grid order up to maxScannedPods, and those that restarted since they
+// were. Pods already scanned, or whose logs could not be read, keep their
+// count until r reloads the pods or the time range changes.
+func (m Model) unscannedPods() []PodInfo {
+	scanned, _ := m.scannedPods()
+	var pods []PodInfo
+	for _, pod := range m.pods {
+		scan, ok := m.podErrors[podScanKey(pod.Context, m.namespace, pod.Name)]
+		switch {
+		case ok && scan.restarts != pod.Restarts:
+			pods = append(pods, pod)
+		case !ok && scanned < maxScannedPods:
+			pods = append(pods, pod)
+			scanned++
+		}
+	}
+	return pods
+}
+
+// scannedPods returns how many of the listed pods had their logs scanned,
+// and how many are listed
+func (m Model) scannedPods() (int, int) {
+	scanned := 0
+	for _, pod := range m.allPods {
+		if _, ok := m.podErrors[podScanKey(pod.Context, m.namespace, pod.Name)]; ok {
+			scanned++
+		}
+	}
+	return scanned, len(m.allPods)
+}
+
+// scanStatus tells that errors are only counted for some of the pods once
+// the scan reached maxScannedPods
+func (m Model) scanStatus() string {
+	scanned, total := m.scannedPods()
+	if scanned < maxScannedPods || scanned == total {
+		return ""
+	}
+	return NormalStyle.Render(fmt.Sprintf("%s: %d/%d", m.localization.ScannedPods, scanned, total)) + "\n\n"
+}
+
+// podTotals sums up the state of a set of pods
+type podTotals struct {
+	pods     int
+	ready    int
+	restarts int
+	errors   int
+	scanned  int // Pods whose error lines were counted
+}
+
+// add counts a pod into the totals
+func (t *podTotals) add(pod PodInfo, errors int) {
+	t.pods++
+	if pod.Ready == "True" {
+		t.ready++
+	}
+	if restarts, err := strconv.Atoi(pod.Restarts); err == nil {
+		t.restarts += restarts
+	}
+	if errors >= 0 {
+		t.errors += errors
+		t.scanned++
+	}
+}
+
+// renderGroupHeader renders the header row of a pod group with the totals
+// of its pods
+func (m Model) renderGroupHeader(row gridRow, selected bool) string {
+	var totals podTotals
+	for _, i := range row.pods {
+		totals.add(m.pods[i], m.podErrorCount(m.pods[i]))
+	}
+
+	marker := "▾"
+	if m.collapsed[row.group] && !m.flatPods {
+		marker = "▸"
+	}
+	label := m.groupLabel(m.pods[row.pods[0]])
+	if selected {
+		label = SelectedStyle.Render(label)
+	} else {
+		label = m.contextStyle(m.pods[row.pods[0]].Context).Render(label)
+	}
+	if m.flatPods {
+		return "⎈ " + label
+	}
+
+	readyStyle := SuccessStyle
+	if totals.ready < totals.pods {
+		readyStyle = WarningStyle
+	}
+	line := fmt.Sprintf("%s %s  %s %s  %s %d",
+		marker, label,
+		readyStyle.Render(fmt.Sprintf("%d/%d", totals.ready, totals.pods)), m.localization.Ready,
+		m.localization.Restart, totals.restarts)
+	if totals.scanned > 0 {
+		errorStyle := SuccessStyle
+		if totals.errors > 0 {
+			errorStyle = ErrorStyle
+		}
+		line += fmt.Sprintf("  %s %s", m.localization.Errors, errorStyle.Render(strconv.Itoa(totals.errors)))
+	}
+	return line
+}
+
+// maxSourceWidth caps the pod column of a merged workload analysis
+const maxSourceWidth = 30
+
+// listedPods is the analyzed workload when every listed pod of a cluster is
+// analyzed together, such as all pods matching the label selector
+const listedPods = "\x00*"
+
+// workloadPods returns the pods of the analyzed workload. A workload has
+// all its replicas analyzed, even those hidden by the name filter.
+func (m Model) workloadPods() []PodInfo {
+	var pods []PodInfo
+	if m.workload == listedPods {
+		for _, pod := range m.pods {
+			if pod.Context == m.pods[m.selectedPod].Context {
+				pods = append(pods, pod)
+			}
+		}
+		return pods
+	}
+	for _, pod := range m.allPods {
+		if m.podGroup(pod) == m.workload {
+			pods = append(pods, pod)
+		}
+	}
+	return pods
+}
+
+// analyzeWorkload opens the merged analysis of every replica of a workload
+func (m *Model) analyzeWorkload(workload string) tea.Cmd {
+	m.workload = workload
+	m.container = AllContainers
+	m.logOffset = 0
+	m.resetTraces()
+	m.clearSearch()
+	m.setTab(TabAll)
+	return m.loadLogs()
+}
+
+// workloadTarget returns the log target covering every replica of the
+// analyzed workload
+func (m Model) workloadTarget() logTarget {
+	target := logTarget{container: AllContainers}
+	for _, pod := range m.workloadPods() {
+		target.context = pod.Context
+		target.workload = pod.Workload
+		if m.workload == listedPods {
+			target.workload = m.localization.AllPods
+			if m.podOptions.LabelSelector != "" {
+				target.workload = m.podOptions.LabelSelector
+			}
+		}
+		if replica := logTargetFor(pod, AllContainers); len(replica.containers) > 0 {
+			target.replicas = append(target.replicas, replica)
+		}
+	}
+	return target
+}
+
+// loadWorkloadLogs fetches the logs of every replica of a workload
+// concurrently and analyzes them interleaved by timestamp, each line tagged
+// with its pod
+func loadWorkloadLogs(ctx context.Context, client ClusterClient, namespace string, target logTarget, opts LogOptions, rules *RuleSet) (LogAnalysis, error) {
+	streams := target.streams()
+	opts.Timestamps = true
+	outputs := make([]string, len(streams))
+	errs := make([]error, len(streams))
+	sem := make(chan struct{}, reportConcurrency)
+	var wg sync.WaitGroup
+	for i, stream := range streams {
+		streamOpts := opts
+		streamOpts.Container = stream.container
+		wg.Add(1)
+		go func(i int, pod string, opts LogOptions) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			outputs[i], errs[i] = fetchLogs(ctx, client, namespace, pod, opts)
+		}(i, stream.pod, streamOpts)
+	}
+	wg.Wait()
+
+	// Only fail when no replica could be read at all
+	var sources []taggedLogs
+	var firstErr error
+	for i, stream := range streams {
+		if errs[i] != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("pod %s: %w", stream.pod, errs[i])
+			}
+			continue
+		}
+		sources = append(sources, taggedLogs{tag: stream.tag, logs: outputs[i]})
+	}
+	if len(sources) == 0 && firstErr != nil {
+		return LogAnalysis{}, firstErr
+	}
+
+	return analyzeTimedLogs(sources, true, rules), nil
+}
+
+// workloadTitle describes an analyzed workload for the analysis view title
+func (m Model) workloadTitle(target logTarget) string {
+	title := target.workload
+	if target.context != "" {
+		title = target.context + "/" + title
+	}
+	return fmt.Sprintf("%s (%d %s)", title, len(target.replicas), strings.ToLower(m.localization.Replicas))
+}
+
+// renderWorkloadDetails renders the replicas of the analyzed workload
+func (m Model) renderWorkloadDetails(target logTarget, analysis LogAnalysis) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s (%d):\n", m.localization.Replicas, len(target.replicas)))
+	for _, pod := range m.workloadPods() {
+		b.WriteString(fmt.Sprintf("  %-40s %s  %s: %s  %s: %s\n",
+			pod.Name,
+			GetStatusStyle(pod.Status).Render(pod.Status),
+			m.localization.Ready, pod.Ready,
+			m.localization.Restart, pod.Restarts,
+		))
+		if pod.Reason != "" {
+			b.WriteString("    " + WarningStyle.Render(m.truncateLogLine(pod.Reason, max(10, m.width-12))) + "\n")
+		}
+	}
+	b.WriteString(fmt.Sprintf("  %s: %s\n", m.localization.Analysis, analysis.AnalyzedAt.Format("15:04:05")))
+	b.WriteString("  " + m.refreshStatus() + "\n")
+	return b.String()
+}
+
+// replicaErrors is the share of one replica in the errors of a workload
+type replicaErrors struct {
+	pod        string
+	errors     int
+	signatures []int // Error lines per top signature
+}
+
+// replicaBreakdown counts the error lines of every replica of a merged
+// analysis, in total and per signature
+func replicaBreakdown(analysis LogAnalysis, target logTarget, signatures []ErrorSignature) []replicaErrors {
+	breakdown := make([]replicaErrors, len(target.replicas))
+	index := make(map[string]int, len(target.replicas))
+	for i, replica := range target.replicas {
+		breakdown[i] = replicaErrors{pod: replica.pod, signatures: make([]int, len(signatures))}
+		index[replica.pod] = i
+	}
+
+	// Source tags are the pod name, followed by the container name for pods
+	// with several containers
+	replicaOf := func(source string) (int, bool) {
+		pod, _, _ := strings.Cut(source, "/")
+		i, ok := index[pod]
+		return i, ok
+	}
+	for _, entry := range analysis.Entries {
+		if entry.Category != "error" {
+			continue
+		}
+		if i, ok := replicaOf(entry.Source); ok {
+			breakdown[i].errors++
+		}
+	}
+	for s, sig := range signatures {
+		for source, count := range sig.Sources {
+			if i, ok := replicaOf(source); ok {
+				breakdown[i].signatures[s] += count
+			}
+		}
+	}
+	return breakdown
+}
+
+// renderReplicaBreakdown renders which replicas of a workload logged which
+// of its top error signatures
+func (m Model) renderReplicaBreakdown(analysis LogAnalysis, target logTarget, signatures []ErrorSignature) string {
+	var b strings.Builder
+	b.WriteString(m.localization.ReplicaBreakdown + ":\n")
+	header := fmt.Sprintf("  %-40s %6s", m.localization.Pod, m.localization.Errors)
+	for i := range signatures {
+		header += fmt.Sprintf(" %5s", fmt.Sprintf("#%d", i+1))
+	}
+	b.WriteString(header + "\n")
+
+	for _, replica := range replicaBreakdown(analysis, target, signatures) {
+		errorStyle := SuccessStyle
+		if replica.errors > 0 {
+			errorStyle = ErrorStyle
+		}
+		line := fmt.Sprintf("  %-40s %s", replica.pod, errorStyle.Render(fmt.Sprintf("%6d", replica.errors)))
+		for _, count := range replica.signatures {
+			if count == 0 {
+				line += NormalStyle.Render(fmt.Sprintf(" %5s", "-"))
+			} else {
+				line += ErrorStyle.Render(fmt.Sprintf(" %5d", count))
+			}
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// sourceWidth returns the width of the pod column of a merged analysis
+func sourceWidth(target logTarget) int {
+	width := 0
+	for _, stream := range target.streams() {
+		width = max(width, len([]rune(stream.tag)))
+	}
+	return min(width, maxSourceWidth)
+}